@@ -17,6 +17,7 @@ type Arguments struct {
 	Config string   // Argument for config file path
 	Lock   string   // Argument for lock file path
 	Log    string   // Argument for log file path
+	Socket string   // Argument for socket file path
 	VVV    bool     // Argument for very very verbose mode
 	VV     bool     // Argument for very verbose mode
 	V      bool     // Argument for verbose mode
@@ -27,6 +28,15 @@ type Arguments struct {
 		Property string   // Argument for dbus property name
 		P        []string // Argument for dbus positional values
 	}
+	Msg struct {
+		P []string // Argument for msg positional values
+	}
+	Status struct {
+		Enabled bool   // Argument for status subcommand
+		Format  string // Argument for status format template
+		Json    bool   // Argument for status json mode
+		Screen  int    // Argument for status screen index
+	}
 }
 
 func InitArgs(introspect map[string][]string) {
@@ -36,6 +46,7 @@ func InitArgs(introspect map[string][]string) {
 	flag.StringVar(&Args.Config, "config", filepath.Join(ConfigFolderPath(Build.Name), "config.toml"), "config file path")
 	flag.StringVar(&Args.Lock, "lock", filepath.Join(os.TempDir(), fmt.Sprintf("%s.lock", Build.Name)), "lock file path")
 	flag.StringVar(&Args.Log, "log", filepath.Join(os.TempDir(), fmt.Sprintf("%s.log", Build.Name)), "log file path")
+	flag.StringVar(&Args.Socket, "socket", filepath.Join(RuntimeFolderPath(), fmt.Sprintf("%s.sock", Build.Name)), "socket file path")
 	flag.BoolVar(&Args.VVV, "vvv", false, "very very verbose mode")
 	flag.BoolVar(&Args.VV, "vv", false, "very verbose mode")
 	flag.BoolVar(&Args.V, "v", false, "verbose mode")
@@ -58,6 +69,15 @@ func InitArgs(introspect map[string][]string) {
 	dbus.StringVar(&Args.Dbus.Property, "property", "", "dbus property reader")
 	Args.Dbus.P = []string{}
 
+	msg := flag.NewFlagSet("msg", flag.ExitOnError)
+	msg.StringVar(&Args.Socket, "socket", Args.Socket, "socket file path")
+	Args.Msg.P = []string{}
+
+	status := flag.NewFlagSet("status", flag.ExitOnError)
+	status.StringVar(&Args.Status.Format, "format", "{{if .Tiling}}{{.Layout}} {{.Masters}}:{{.Slaves}}{{else}}disabled{{end}} [{{.Clients}}]", "status format template")
+	status.BoolVar(&Args.Status.Json, "json", false, "status json mode (i3blocks/waybar)")
+	status.IntVar(&Args.Status.Screen, "screen", -1, "status screen index (-1 for current screen)")
+
 	if len(os.Args) > 1 {
 		switch os.Args[1] {
 		case "dbus":
@@ -80,6 +100,12 @@ func InitArgs(introspect map[string][]string) {
 							fmt.Fprintf(dbus.Output(), "  %s dbus -property %s\n", Build.Name, property)
 						}
 					}
+					if signals, ok := introspect["Signals"]; ok {
+						fmt.Fprintf(dbus.Output(), "\nSignals:\n")
+						for _, signal := range signals {
+							fmt.Fprintf(dbus.Output(), "  %s dbus -listen Signal:%s\n", Build.Name, signal)
+						}
+					}
 				} else {
 					fmt.Fprintf(dbus.Output(), "\n>>> start %s to see further information's <<<\n", Build.Name)
 				}
@@ -94,10 +120,78 @@ func InitArgs(introspect map[string][]string) {
 				dbus.Usage()
 				os.Exit(2)
 			}
+		case "msg":
+
+			// Subcommand line usage text
+			msg.Usage = func() {
+				fmt.Fprintf(msg.Output(), "%s\n\nUsage:\n", Build.Summary)
+				msg.PrintDefaults()
+
+				fmt.Fprintf(msg.Output(), "\nCommands:\n")
+				for _, command := range []string{
+					"action str:name [int:desktop int:screen]",
+					"layout [int:desktop int:screen]",
+					"workspaces",
+					"clients",
+					"config",
+					"subscribe [str:event ...]",
+				} {
+					fmt.Fprintf(msg.Output(), "  %s msg %s\n", Build.Name, command)
+				}
+			}
+
+			// Parse subcommand line arguments
+			FlagParse(msg, os.Args[2:])
+			Args.Msg.P = msg.Args()
+
+			// Check subcommand line arguments
+			if len(Args.Msg.P) == 0 {
+				msg.Usage()
+				os.Exit(2)
+			}
+		case "status":
+
+			// Subcommand line usage text
+			status.Usage = func() {
+				fmt.Fprintf(status.Output(), "%s\n\nUsage:\n", Build.Summary)
+				status.PrintDefaults()
+
+				fmt.Fprintf(status.Output(), "\nFields:\n")
+				for _, field := range []string{
+					"{{.Desktop}} current desktop index",
+					"{{.Screen}} current screen index",
+					"{{.Workspace}} workspace name",
+					"{{.Layout}} active layout name",
+					"{{.Tiling}} tiling is enabled",
+					"{{.Masters}} maximum number of masters",
+					"{{.Slaves}} maximum number of slaves",
+					"{{.Clients}} number of windows",
+					"{{.Title}} focused window title",
+					"{{.Class}} focused window class",
+					"{{.Action}} last executed action",
+				} {
+					fmt.Fprintf(status.Output(), "  %s\n", field)
+				}
+			}
+
+			// Parse subcommand line arguments
+			FlagParse(status, os.Args[2:])
+			Args.Status.Enabled = true
 		}
 	}
 }
 
+func RuntimeFolderPath() string {
+
+	// Obtain user runtime directory
+	userRuntimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if len(userRuntimeDir) == 0 {
+		return os.TempDir()
+	}
+
+	return userRuntimeDir
+}
+
 func FlagParse(flags *flag.FlagSet, args []string) {
 	pargs := []string{}
 
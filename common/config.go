@@ -19,30 +19,44 @@ var (
 )
 
 type Configuration struct {
-	TilingEnabled     bool              `toml:"tiling_enabled"`      // Tile windows on startup
-	TilingLayout      string            `toml:"tiling_layout"`       // Initial tiling layout
-	TilingCycle       []string          `toml:"tiling_cycle"`        // Cycle layout order
-	TilingGui         int               `toml:"tiling_gui"`          // Time duration of gui
-	TilingIcon        [][]string        `toml:"tiling_icon"`         // Menu entries of systray
-	WindowIgnore      [][]string        `toml:"window_ignore"`       // Regex to ignore windows
-	WindowMastersMax  int               `toml:"window_masters_max"`  // Maximum number of allowed masters
-	WindowSlavesMax   int               `toml:"window_slaves_max"`   // Maximum number of allowed slaves
-	WindowGapSize     int               `toml:"window_gap_size"`     // Gap size between windows
-	WindowFocusDelay  int               `toml:"window_focus_delay"`  // Window focus delay when hovered
-	WindowDecoration  bool              `toml:"window_decoration"`   // Show window decorations
-	UltrawideThreshold int               `toml:"ultrawide_threshold"` // Screen width to trigger autotile
-	AutotileColumnsMax int               `toml:"autotile_columns_max"` // Maximum columns for autotile
-	AutotileColumnsDefault int           `toml:"autotile_columns_default"` // Default columns for autotile
-	ProportionStep    float64           `toml:"proportion_step"`     // Master-slave area step size proportion
-	ProportionMin     float64           `toml:"proportion_min"`      // Window size minimum proportion
-	EdgeMargin        []int             `toml:"edge_margin"`         // Margin values of tiling area
-	EdgeMarginPrimary []int             `toml:"edge_margin_primary"` // Margin values of primary tiling area
-	EdgeCornerSize    int               `toml:"edge_corner_size"`    // Size of square defining edge corners
-	EdgeCenterSize    int               `toml:"edge_center_size"`    // Length of rectangle defining edge centers
-	Colors            map[string][]int  `toml:"colors"`              // List of color values for gui elements
-	Keys              map[string]string `toml:"keys"`                // Event bindings for keyboard shortcuts
-	Corners           map[string]string `toml:"corners"`             // Event bindings for hot-corner actions
-	Systray           map[string]string `toml:"systray"`             // Event bindings for systray icon
+	TilingEnabled          bool                         `toml:"tiling_enabled"`           // Tile windows on startup
+	TilingLayout           string                       `toml:"tiling_layout"`            // Initial tiling layout
+	TilingCycle            []string                     `toml:"tiling_cycle"`             // Cycle layout order
+	TilingGui              int                          `toml:"tiling_gui"`               // Time duration of gui
+	TilingIcon             [][]string                   `toml:"tiling_icon"`              // Menu entries of systray
+	WindowIgnore           [][]string                   `toml:"window_ignore"`            // Regex to ignore windows
+	WindowMastersMax       int                          `toml:"window_masters_max"`       // Maximum number of allowed masters
+	WindowSlavesMax        int                          `toml:"window_slaves_max"`        // Maximum number of allowed slaves
+	WindowGapSize          int                          `toml:"window_gap_size"`          // Gap size between windows
+	WindowFocusDelay       int                          `toml:"window_focus_delay"`       // Window focus delay when hovered
+	WindowFocusWarp        bool                         `toml:"window_focus_warp"`        // Warp pointer to focused window
+	WindowDecoration       bool                         `toml:"window_decoration"`        // Show window decorations
+	WindowCrossScreens     bool                         `toml:"window_cross_screens"`     // Directional actions cross screens
+	WindowHintsChars       string                       `toml:"window_hints_chars"`       // Characters used for hint labels
+	UltrawideThreshold     int                          `toml:"ultrawide_threshold"`      // Screen width to trigger autotile
+	AutotileColumnsMax     int                          `toml:"autotile_columns_max"`     // Maximum columns for autotile
+	AutotileColumnsDefault int                          `toml:"autotile_columns_default"` // Default columns for autotile
+	ProportionStep         float64                      `toml:"proportion_step"`          // Master-slave area step size proportion
+	ProportionMin          float64                      `toml:"proportion_min"`           // Window size minimum proportion
+	EdgeMargin             []int                        `toml:"edge_margin"`              // Margin values of tiling area
+	EdgeMarginPrimary      []int                        `toml:"edge_margin_primary"`      // Margin values of primary tiling area
+	EdgeCornerSize         int                          `toml:"edge_corner_size"`         // Size of square defining edge corners
+	EdgeCenterSize         int                          `toml:"edge_center_size"`         // Length of rectangle defining edge centers
+	EdgeCornerDelay        int                          `toml:"edge_corner_delay"`        // Time the pointer has to stay in a corner
+	EdgeCornerCooldown     int                          `toml:"edge_corner_cooldown"`     // Time until a corner can fire again
+	EdgeCornerTravel       int                          `toml:"edge_corner_travel"`       // Distance the pointer has to move along a corner edge
+	EdgeDropSize           int                          `toml:"edge_drop_size"`           // Width of drop zones at desktop edges
+	KeysSequenceTimeout    int                          `toml:"keys_sequence_timeout"`    // Time to wait for keys of a sequence
+	ExternalTimeout        int                          `toml:"external_timeout"`         // Time until external commands are terminated
+	Colors                 map[string][]int             `toml:"colors"`                   // List of color values for gui elements
+	Keys                   map[string]string            `toml:"keys"`                     // Event bindings for keyboard shortcuts
+	Modes                  map[string]map[string]string `toml:"modes"`                    // Event bindings for keyboard modes
+	Macros                 map[string]string            `toml:"macros"`                   // Named lists of chained actions
+	Mouse                  map[string]string            `toml:"mouse"`                    // Event bindings for mouse buttons
+	Corners                map[string]string            `toml:"corners"`                  // Event bindings for hot-corner actions
+	Screens                map[string]map[string]string `toml:"screens"`                  // Screen specific hot-corner bindings
+	Systray                map[string]string            `toml:"systray"`                  // Event bindings for systray icon
+	Hooks                  map[string]string            `toml:"hooks"`                    // Commands executed on state changes
 }
 
 func InitConfig() {
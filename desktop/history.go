@@ -0,0 +1,195 @@
+package desktop
+
+import (
+	"time"
+
+	"github.com/jezek/xgb/xproto"
+
+	"github.com/leukipp/cortile/v2/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type History struct {
+	Global     []xproto.Window                    // Recently focused windows (most recent first)
+	Workspaces map[store.Location][]xproto.Window // Recently focused windows per workspace
+	Desktops   []uint                             // Recently active desktops (most recent first)
+	Focused    int64                              // Last focus change timestamp
+	Cycle      *Cycle                             // Helper for recently used cycling
+}
+
+type Cycle struct {
+	Windows []xproto.Window // Snapshot of recently focused windows
+	Index   int             // Current position within snapshot
+	Timer   *time.Timer     // Timer to finish cycling
+}
+
+func CreateHistory() *History {
+	return &History{
+		Global:     []xproto.Window{},
+		Workspaces: make(map[store.Location][]xproto.Window),
+		Desktops:   []uint{store.Workplace.CurrentDesktop},
+	}
+}
+
+func (h *History) Add(w xproto.Window, loc store.Location) {
+	h.Remove(w)
+
+	// Move window to front
+	h.Global = append([]xproto.Window{w}, h.Global...)
+	h.Workspaces[loc] = append([]xproto.Window{w}, h.Workspaces[loc]...)
+	h.Focused = time.Now().UnixMilli()
+}
+
+func (h *History) Remove(w xproto.Window) int {
+	index := indexWindow(h.Global, w)
+
+	// Remove window from lists
+	if index >= 0 {
+		h.Global = append(h.Global[:index], h.Global[index+1:]...)
+	}
+	for loc, windows := range h.Workspaces {
+		if i := indexWindow(windows, w); i >= 0 {
+			h.Workspaces[loc] = append(windows[:i], windows[i+1:]...)
+		}
+	}
+
+	return index
+}
+
+func (h *History) AddDesktop(desktop uint) {
+	if len(h.Desktops) > 0 && h.Desktops[0] == desktop {
+		return
+	}
+
+	// Keep current and previous desktop
+	h.Desktops = append([]uint{desktop}, h.Desktops...)
+	if len(h.Desktops) > 2 {
+		h.Desktops = h.Desktops[:2]
+	}
+}
+
+func (h *History) PreviousDesktop() (uint, bool) {
+	if len(h.Desktops) < 2 {
+		return 0, false
+	}
+	return h.Desktops[1], true
+}
+
+func (h *History) Windows(loc *store.Location) []xproto.Window {
+	if loc == nil {
+		return h.Global
+	}
+	return h.Workspaces[*loc]
+}
+
+func (tr *Tracker) RecentClient(loc *store.Location) *store.Client {
+
+	// Obtain most recent client other than the active one
+	for _, w := range tr.History.Windows(loc) {
+		if c, ok := tr.Clients[w]; ok && w != store.Windows.Active.Id {
+			return c
+		}
+	}
+
+	return nil
+}
+
+func (tr *Tracker) CycleClient(ws *Workspace, dir int) *store.Client {
+	h := tr.History
+
+	// Start cycle with snapshot of workspace history
+	if h.Cycle == nil {
+		windows := append([]xproto.Window{}, h.Windows(&ws.Location)...)
+		if len(windows) < 2 {
+			return nil
+		}
+		h.Cycle = &Cycle{Windows: windows}
+	} else {
+		h.Cycle.Timer.Stop()
+	}
+	cy := h.Cycle
+
+	// Finish cycle when no further cycling happens
+	cy.Timer = time.AfterFunc(1000*time.Millisecond, func() {
+		store.Enqueue(func() {
+			if h.Cycle != cy {
+				return
+			}
+			h.Cycle = nil
+			tr.updateHistory()
+		})
+	})
+
+	// Obtain next/previous tracked client
+	for range cy.Windows {
+		cy.Index = (cy.Index + dir + len(cy.Windows)) % len(cy.Windows)
+		if c, ok := tr.Clients[cy.Windows[cy.Index]]; ok {
+			return c
+		}
+	}
+
+	return nil
+}
+
+func (tr *Tracker) updateHistory() {
+	c := tr.ActiveClient()
+	if c == nil {
+		return
+	}
+
+	// Ignore focus changes caused by cycling
+	if cy := tr.History.Cycle; cy != nil {
+		if cy.Windows[cy.Index] == c.Window.Id {
+			return
+		}
+		cy.Timer.Stop()
+		tr.History.Cycle = nil
+	}
+
+	// Move active client to front
+	tr.History.Add(c.Window.Id, c.Latest.Location)
+}
+
+func (tr *Tracker) restoreFocus(index int, loc store.Location) {
+	h := tr.History
+
+	// Window managers may focus another window before the closed one is removed
+	recent := time.Since(time.UnixMilli(h.Focused)) < 500*time.Millisecond
+	if index != 0 && (index != 1 || !recent) {
+		return
+	}
+	var skip xproto.Window
+	if index == 1 {
+		skip = h.Global[0]
+	}
+
+	// Obtain previous client of the same workspace
+	var c *store.Client
+	for _, w := range h.Workspaces[loc] {
+		if wc, ok := tr.Clients[w]; ok && w != skip {
+			c = wc
+			break
+		}
+	}
+
+	// Fall back to previous client of any workspace
+	if c == nil && index < len(h.Global) {
+		c = tr.Clients[h.Global[index]]
+	}
+	if c == nil || c.Window.Id == store.Windows.Active.Id {
+		return
+	}
+	log.Debug("Restore focus of previous client [", c.Latest.Class, "]")
+
+	store.ActiveWindowSet(store.X, c.Window)
+}
+
+func indexWindow(windows []xproto.Window, w xproto.Window) int {
+	for i, wi := range windows {
+		if wi == w {
+			return i
+		}
+	}
+	return -1
+}
@@ -1,6 +1,7 @@
 package desktop
 
 import (
+	"sort"
 	"time"
 
 	"github.com/jezek/xgb/xproto"
@@ -10,6 +11,7 @@ import (
 	"github.com/jezek/xgbutil/xprop"
 
 	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/layout"
 	"github.com/leukipp/cortile/v2/store"
 
 	log "github.com/sirupsen/logrus"
@@ -20,7 +22,10 @@ type Tracker struct {
 	Workspaces     map[store.Location]*Workspace   // List of workspaces per location
 	Channels       *Channels                       // Helper for channel communication
 	Handlers       *Handlers                       // Helper for event handlers
+	History        *History                        // Helper for focus history
 	FloatedWindows map[xproto.Window]bool          // Windows manually excluded from tiling
+	UrgentWindows  map[xproto.Window]int64         // Windows demanding attention since timestamp
+	Deferred       map[*Workspace]bool             // Workspaces to tile after a transaction
 
 }
 type Channels struct {
@@ -34,10 +39,11 @@ type Handlers struct {
 	MoveClient   *Handler    // Stores client for tiling after move
 	SwapClient   *Handler    // Stores clients for window swap
 	SwapScreen   *Handler    // Stores client for screen swap
+	InsertClient *Handler    // Stores client for drop zone insert
 }
 
 func (h *Handlers) Active() bool {
-	return h.ResizeClient.Active() || h.MoveClient.Active() || h.SwapClient.Active() || h.SwapScreen.Active()
+	return h.ResizeClient.Active() || h.MoveClient.Active() || h.SwapClient.Active() || h.SwapScreen.Active() || h.InsertClient.Active()
 }
 
 func (h *Handlers) Reset() {
@@ -45,6 +51,7 @@ func (h *Handlers) Reset() {
 	h.MoveClient.Reset()
 	h.SwapClient.Reset()
 	h.SwapScreen.Reset()
+	h.InsertClient.Reset()
 }
 
 type Handler struct {
@@ -66,6 +73,8 @@ func CreateTracker() *Tracker {
 		Clients:        make(map[xproto.Window]*store.Client),
 		Workspaces:     CreateWorkspaces(),
 		FloatedWindows: make(map[xproto.Window]bool),
+		UrgentWindows:  make(map[xproto.Window]int64),
+		History:        CreateHistory(),
 		Channels: &Channels{
 			Event:  make(chan string),
 			Action: make(chan string),
@@ -75,6 +84,7 @@ func CreateTracker() *Tracker {
 			MoveClient:   &Handler{},
 			SwapClient:   &Handler{},
 			SwapScreen:   &Handler{},
+			InsertClient: &Handler{},
 		},
 	}
 
@@ -149,6 +159,12 @@ func (tr *Tracker) Tile(ws *Workspace) {
 		return
 	}
 
+	// Defer tiling within transactions
+	if tr.Deferred != nil {
+		tr.Deferred[ws] = true
+		return
+	}
+
 	// Tile workspace
 	ws.Tile()
 
@@ -159,6 +175,24 @@ func (tr *Tracker) Tile(ws *Workspace) {
 	tr.Channels.Event <- "workspaces_change"
 }
 
+func (tr *Tracker) Transaction(fun func()) {
+	if tr.Deferred != nil {
+		fun()
+		return
+	}
+
+	// Collect workspaces to tile
+	tr.Deferred = make(map[*Workspace]bool)
+	fun()
+	deferred := tr.Deferred
+	tr.Deferred = nil
+
+	// Tile workspaces once
+	for ws := range deferred {
+		tr.Tile(ws)
+	}
+}
+
 func (tr *Tracker) Restore(ws *Workspace, flag uint8) {
 
 	// Restore workspace
@@ -215,6 +249,155 @@ func (tr *Tracker) ClientAt(ws *Workspace, p common.Point) *store.Client {
 	return nil
 }
 
+func (tr *Tracker) DropZone(ws *Workspace, p common.Point) string {
+	size := common.Config.EdgeDropSize
+	if ws == nil || ws.TilingDisabled() || size <= 0 {
+		return ""
+	}
+
+	// Obtain master side of layout
+	side, ok := map[string]string{
+		"vertical-left":     "left",
+		"vertical-right":    "right",
+		"horizontal-top":    "top",
+		"horizontal-bottom": "bottom",
+		"autotile":          "left",
+	}[ws.ActiveLayout().GetName()]
+	if !ok {
+		return ""
+	}
+
+	// Check if point hovers a desktop edge
+	x, y, w, h := store.DesktopGeometry(ws.Location.Screen).Pieces()
+	edges := map[string]bool{
+		"left":   p.X < x+size,
+		"right":  p.X >= x+w-size,
+		"top":    p.Y < y+size,
+		"bottom": p.Y >= y+h-size,
+	}
+	opposite := map[string]string{
+		"left":   "right",
+		"right":  "left",
+		"top":    "bottom",
+		"bottom": "top",
+	}[side]
+
+	// Master zone on master side, slave zone on opposite side
+	if edges[side] {
+		return "master"
+	}
+	if edges[opposite] {
+		return "slave"
+	}
+
+	return ""
+}
+
+func (tr *Tracker) ClientInDirection(c *store.Client, d *store.Directions) *store.Client {
+	ws := tr.ClientWorkspace(c)
+	if ws == nil {
+		return nil
+	}
+
+	// Check nearest client on same screen
+	if co := nearestClient(c, ws.VisibleClients(), d); co != nil {
+		return co
+	}
+	if !common.Config.WindowCrossScreens {
+		return nil
+	}
+
+	// Check nearest client on adjacent screen
+	screen, ok := store.ScreenNeighbor(c.Latest.Location.Screen, d)
+	if !ok {
+		return nil
+	}
+	wo := tr.WorkspaceAt(ws.Location.Desktop, screen)
+	if wo == nil {
+		return nil
+	}
+
+	return nearestClient(c, wo.VisibleClients(), d)
+}
+
+func (tr *Tracker) SwapClients(c1 *store.Client, c2 *store.Client) {
+	ws1, ws2 := tr.ClientWorkspace(c1), tr.ClientWorkspace(c2)
+	if ws1 == nil || ws2 == nil {
+		return
+	}
+
+	// Swap clients on same desktop and screen
+	if ws1 == ws2 {
+		mg := ws1.ActiveLayout().GetManager()
+		mg.SwapClient(c1, c2)
+
+		// Tile workspace
+		tr.Tile(ws1)
+		return
+	}
+	log.Debug("Swap clients between workspaces [", ws1.Name, "-", ws2.Name, "]")
+
+	// Exchange clients while keeping their layout positions
+	ws1.AddClient(c2)
+	ws2.AddClient(c1)
+	for _, l := range ws1.Layouts {
+		l.GetManager().SwapClient(c2, c1)
+	}
+	for _, l := range ws2.Layouts {
+		l.GetManager().SwapClient(c1, c2)
+	}
+	ws1.RemoveClient(c1)
+	ws2.RemoveClient(c2)
+
+	// Update client locations
+	c1.Latest.Location, c2.Latest.Location = c2.Latest.Location, c1.Latest.Location
+
+	// Tile both workspaces
+	tr.Tile(ws1)
+	tr.Tile(ws2)
+}
+
+func (tr *Tracker) SwapWorkspaces(ws1 *Workspace, ws2 *Workspace) bool {
+	if ws1 == nil || ws2 == nil || ws1 == ws2 || ws1.TilingDisabled() || ws2.TilingDisabled() {
+		return false
+	}
+	log.Debug("Swap workspaces between screens [", ws1.Name, "-", ws2.Name, "]")
+
+	// Exchange clients and proportions of each layout
+	for i := range ws1.Layouts {
+		mg1, mg2 := ws1.Layouts[i].GetManager(), ws2.Layouts[i].GetManager()
+		mg1.Masters, mg2.Masters = mg2.Masters, mg1.Masters
+		mg1.Slaves, mg2.Slaves = mg2.Slaves, mg1.Slaves
+		mg1.Proportions, mg2.Proportions = mg2.Proportions, mg1.Proportions
+		mg1.Decoration, mg2.Decoration = mg2.Decoration, mg1.Decoration
+
+		// Exchange columns of autotile layout
+		al1, ok1 := ws1.Layouts[i].(*layout.AutotileLayout)
+		al2, ok2 := ws2.Layouts[i].(*layout.AutotileLayout)
+		if ok1 && ok2 {
+			al1.Columns, al2.Columns = al2.Columns, al1.Columns
+			al1.ColumnProps, al2.ColumnProps = al2.ColumnProps, al1.ColumnProps
+		}
+	}
+
+	// Exchange active layout
+	ws1.Layout, ws2.Layout = ws2.Layout, ws1.Layout
+
+	// Update client locations
+	for _, c := range ws1.ActiveLayout().GetManager().Clients(store.Stacked) {
+		c.Latest.Location = ws1.Location
+	}
+	for _, c := range ws2.ActiveLayout().GetManager().Clients(store.Stacked) {
+		c.Latest.Location = ws2.Location
+	}
+
+	// Tile both workspaces
+	tr.Tile(ws1)
+	tr.Tile(ws2)
+
+	return true
+}
+
 func (tr *Tracker) ActiveClient() *store.Client {
 	c, exists := tr.Clients[store.Windows.Active.Id]
 
@@ -226,6 +409,24 @@ func (tr *Tracker) ActiveClient() *store.Client {
 	return c
 }
 
+func (tr *Tracker) UrgentClients() []*store.Client {
+	clients := []*store.Client{}
+
+	// Obtain urgent clients
+	for w := range tr.UrgentWindows {
+		if c, ok := tr.Clients[w]; ok {
+			clients = append(clients, c)
+		}
+	}
+
+	// Sort by urgency age (oldest first)
+	sort.Slice(clients, func(i, j int) bool {
+		return tr.UrgentWindows[clients[i].Window.Id] < tr.UrgentWindows[clients[j].Window.Id]
+	})
+
+	return clients
+}
+
 func (tr *Tracker) unlockClients() {
 	ws := tr.ActiveWorkspace()
 	if ws == nil {
@@ -260,6 +461,7 @@ func (tr *Tracker) trackWindow(w xproto.Window) bool {
 
 	// Attach handlers
 	tr.attachHandlers(c)
+	tr.handleUrgentClient(c)
 	tr.Tile(ws)
 
 	return true
@@ -286,10 +488,22 @@ func (tr *Tracker) untrackWindow(w xproto.Window) bool {
 	// Remove client
 	ws.RemoveClient(c)
 	delete(tr.Clients, w)
+	index := tr.History.Remove(w)
+
+	// Remove urgency
+	if _, ok := tr.UrgentWindows[w]; ok {
+		delete(tr.UrgentWindows, w)
+		tr.Channels.Event <- "urgency_change"
+	}
 
 	// Tile workspace
 	tr.Tile(ws)
 
+	// Focus previous client of closed window
+	if !tr.isStacked(w) {
+		tr.restoreFocus(index, c.Latest.Location)
+	}
+
 	return true
 }
 
@@ -338,6 +552,30 @@ func (tr *Tracker) handleMinimizedClient(c *store.Client) {
 	}
 }
 
+func (tr *Tracker) handleUrgentClient(c *store.Client) {
+	if !tr.isTracked(c.Window.Id) {
+		return
+	}
+
+	// Compare client urgency
+	_, marked := tr.UrgentWindows[c.Window.Id]
+	urgent := store.IsUrgent(store.GetInfo(c.Window.Id))
+	if urgent == marked {
+		return
+	}
+	log.Debug("Client urgency handler fired [", c.Latest.Class, "]")
+
+	// Update client urgency
+	if urgent {
+		tr.UrgentWindows[c.Window.Id] = time.Now().UnixMilli()
+	} else {
+		delete(tr.UrgentWindows, c.Window.Id)
+	}
+
+	// Communicate urgency change
+	tr.Channels.Event <- "urgency_change"
+}
+
 func (tr *Tracker) handleResizeClient(c *store.Client) {
 	ws := tr.ClientWorkspace(c)
 	if ws.TilingDisabled() || !tr.isTracked(c.Window.Id) || store.IsMaximized(store.GetInfo(c.Window.Id)) {
@@ -442,6 +680,14 @@ func (tr *Tracker) handleMoveClient(c *store.Client) {
 			tr.Handlers.SwapScreen = &Handler{Source: c, Target: tr.WorkspaceAt(targetDesktop, targetScreen)}
 			log.Debug("Screen swap handler active [", c.Latest.Class, "]")
 		}
+
+		// Check if target point hovers a drop zone
+		tr.Handlers.InsertClient.Reset()
+		if zone := tr.DropZone(tr.WorkspaceAt(targetDesktop, targetScreen), targetPoint); len(zone) > 0 && tr.Handlers.MoveClient.Dragging {
+			tr.Handlers.SwapClient.Reset()
+			tr.Handlers.InsertClient = &Handler{Source: c, Target: zone}
+			log.Debug("Client insert handler active [", c.Latest.Class, "-", zone, "]")
+		}
 	}
 }
 
@@ -464,6 +710,25 @@ func (tr *Tracker) handleSwapClient(h *Handler) {
 	tr.Tile(ws)
 }
 
+func (tr *Tracker) handleInsertClient(h *Handler) {
+	c, zone := h.Source.(*store.Client), h.Target.(string)
+	ws := tr.ClientWorkspace(c)
+	if !tr.isTracked(c.Window.Id) || ws.TilingDisabled() {
+		return
+	}
+	log.Debug("Client insert handler fired [", c.Latest.Class, "-", zone, "]")
+
+	// Insert client as first master or first slave
+	mg := ws.ActiveLayout().GetManager()
+	mg.InsertClient(c, zone == "master")
+
+	// Reset client insert handler
+	h.Reset()
+
+	// Tile workspace
+	tr.Tile(ws)
+}
+
 func (tr *Tracker) handleWorkspaceChange(h *Handler) {
 	c, target := h.Source.(*store.Client), h.Target.(*Workspace)
 	if !tr.isTracked(c.Window.Id) {
@@ -526,6 +791,9 @@ func (tr *Tracker) onStateUpdate(state string, desktop uint, screen uint) {
 
 	if workspaceChanged {
 
+		// Update desktop history
+		tr.History.AddDesktop(store.Workplace.CurrentDesktop)
+
 		// Update sticky windows
 		for _, c := range tr.Clients {
 			if store.IsSticky(c.Latest) && c.Latest.Location.Desktop != store.Workplace.CurrentDesktop {
@@ -548,6 +816,9 @@ func (tr *Tracker) onStateUpdate(state string, desktop uint, screen uint) {
 
 	if focusChanged {
 
+		// Update focus history
+		tr.updateHistory()
+
 		// Write client and workspace cache
 		tr.Write()
 	}
@@ -580,6 +851,11 @@ func (tr *Tracker) onPointerUpdate(pointer store.XPointer, desktop uint, screen
 			tr.handleSwapClient(tr.Handlers.SwapClient)
 		}
 
+		// Window moved into a drop zone
+		if tr.Handlers.InsertClient.Active() && buttonReleased {
+			tr.handleInsertClient(tr.Handlers.InsertClient)
+		}
+
 		// Window moved or resized
 		if tr.Handlers.MoveClient.Active() || tr.Handlers.ResizeClient.Active() {
 			tr.Handlers.MoveClient.Reset()
@@ -620,6 +896,9 @@ func (tr *Tracker) attachHandlers(c *store.Client) {
 		if aname == "_NET_WM_STATE" {
 			tr.handleMaximizedClient(c)
 			tr.handleMinimizedClient(c)
+			tr.handleUrgentClient(c)
+		} else if aname == "WM_HINTS" {
+			tr.handleUrgentClient(c)
 		} else if aname == "_NET_WM_DESKTOP" {
 			tr.handleWorkspaceChange(&Handler{Source: c, Target: tr.ActiveWorkspace()})
 		}
@@ -631,6 +910,15 @@ func (tr *Tracker) isTracked(w xproto.Window) bool {
 	return ok
 }
 
+func (tr *Tracker) isStacked(w xproto.Window) bool {
+	for _, s := range store.Windows.Stacked {
+		if s.Id == w {
+			return true
+		}
+	}
+	return false
+}
+
 func (tr *Tracker) isTrackable(w xproto.Window) bool {
 	if tr.FloatedWindows[w] {
 		return false
@@ -676,3 +964,21 @@ func (tr *Tracker) ToggleFloat(w xproto.Window) {
 		tr.Tile(ws)
 	}
 }
+
+func nearestClient(c *store.Client, clients []*store.Client, d *store.Directions) *store.Client {
+	var nearest *store.Client
+
+	// Find client with shortest distance in direction
+	best := -1
+	for _, co := range clients {
+		if co == nil || co.Window.Id == c.Window.Id {
+			continue
+		}
+		distance, ok := store.DirectionDistance(c.Latest.Dimensions.Geometry, co.Latest.Dimensions.Geometry, d)
+		if ok && (best < 0 || distance < best) {
+			nearest, best = co, distance
+		}
+	}
+
+	return nearest
+}
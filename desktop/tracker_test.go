@@ -0,0 +1,53 @@
+package desktop
+
+import (
+	"testing"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/layout"
+	"github.com/leukipp/cortile/v2/store"
+)
+
+func TestSwapWorkspaces(t *testing.T) {
+	common.Config.AutotileColumnsMax = 4
+
+	// Create workspaces with different column counts
+	loc1, loc2 := store.Location{Desktop: 0, Screen: 0}, store.Location{Desktop: 0, Screen: 1}
+	ws1 := &Workspace{Name: "workspace-0-0", Location: loc1, Layouts: CreateLayouts(loc1), Tiling: true}
+	ws2 := &Workspace{Name: "workspace-0-1", Location: loc2, Layouts: CreateLayouts(loc2), Tiling: true}
+
+	al1, al2 := autotileLayout(t, ws1), autotileLayout(t, ws2)
+	al1.Columns, al1.ColumnProps = 2, []float64{0.3, 0.7, 0, 0}
+	al2.Columns, al2.ColumnProps = 3, []float64{0.2, 0.3, 0.5, 0}
+
+	// Swap workspaces within a transaction to skip tiling
+	tr := &Tracker{}
+	tr.Transaction(func() {
+		if !tr.SwapWorkspaces(ws1, ws2) {
+			t.Fatal("workspaces not swapped")
+		}
+		tr.Deferred = map[*Workspace]bool{}
+	})
+
+	// Columns follow the swapped workspace
+	if al1.Columns != 3 || al1.ColumnProps[2] != 0.5 {
+		t.Errorf("unexpected columns %d %v on %s", al1.Columns, al1.ColumnProps, ws1.Name)
+	}
+	if al2.Columns != 2 || al2.ColumnProps[1] != 0.7 {
+		t.Errorf("unexpected columns %d %v on %s", al2.Columns, al2.ColumnProps, ws2.Name)
+	}
+}
+
+func autotileLayout(t *testing.T, ws *Workspace) *layout.AutotileLayout {
+	t.Helper()
+
+	// Obtain autotile layout of workspace
+	for _, l := range ws.Layouts {
+		if al, ok := l.(*layout.AutotileLayout); ok {
+			return al
+		}
+	}
+	t.Fatal("no autotile layout in ", ws.Name)
+
+	return nil
+}
@@ -205,7 +205,11 @@ func (ws *Workspace) Tile() {
 		if c == nil {
 			continue
 		}
-		if mg.DecorationEnabled() {
+		decorate := mg.DecorationEnabled()
+		if c.Decoration != nil {
+			decorate = *c.Decoration
+		}
+		if decorate {
 			if c.Decorate() {
 				c.Update()
 			}
@@ -15,6 +15,7 @@ require (
 	github.com/minio/selfupdate v0.6.0
 	github.com/shirou/gopsutil v3.21.11+incompatible
 	github.com/sirupsen/logrus v1.9.4-0.20230606125235-dd1b4c2e81af
+	go.starlark.net v0.0.0-20241226192728-8dfa5b98479f
 	golang.org/x/exp v0.0.0-20241009180824-f66d83c29e7c
 	golang.org/x/image v0.21.0
 )
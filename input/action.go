@@ -1,7 +1,9 @@
 package input
 
 import (
+	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -24,11 +26,15 @@ var (
 )
 
 func Bind(tr *desktop.Tracker) {
+	BindScripts(tr)
+	ValidateActions()
 	BindSignal(tr)
 	BindMouse(tr)
 	BindKeys(tr)
 	BindTray(tr)
 	BindDbus(tr)
+	BindSocket(tr)
+	BindHooks(tr)
 	BindAddons(tr)
 }
 
@@ -90,10 +96,48 @@ func ExecuteAction(action string, tr *desktop.Tracker, ws *desktop.Workspace) bo
 		success = NextWindow(tr, ws)
 	case "window_previous":
 		success = PreviousWindow(tr, ws)
+	case "focus_last":
+		success = FocusLast(tr, ws)
+	case "focus_mru_next":
+		success = FocusRecentNext(tr, ws)
+	case "focus_mru_previous":
+		success = FocusRecentPrevious(tr, ws)
+	case "focus_urgent":
+		success = FocusUrgent(tr, ws)
+	case "window_hints":
+		success = WindowHints(tr, ws)
+	case "window_hints_swap":
+		success = WindowHintsSwap(tr, ws)
+	case "focus_left":
+		success = FocusDirection(tr, ws, &store.Directions{Left: true})
+	case "focus_right":
+		success = FocusDirection(tr, ws, &store.Directions{Right: true})
+	case "focus_up":
+		success = FocusDirection(tr, ws, &store.Directions{Top: true})
+	case "focus_down":
+		success = FocusDirection(tr, ws, &store.Directions{Bottom: true})
+	case "swap_left":
+		success = SwapDirection(tr, ws, &store.Directions{Left: true})
+	case "swap_right":
+		success = SwapDirection(tr, ws, &store.Directions{Right: true})
+	case "swap_up":
+		success = SwapDirection(tr, ws, &store.Directions{Top: true})
+	case "swap_down":
+		success = SwapDirection(tr, ws, &store.Directions{Bottom: true})
 	case "screen_next":
 		success = NextScreen(tr, ws)
 	case "screen_previous":
 		success = PreviousScreen(tr, ws)
+	case "mode_exit":
+		success = DisableMode(tr)
+	case "workspace_swap_screens":
+		success = SwapScreens(tr, ws)
+	case "desktop_next":
+		success = NextDesktop(tr, ws)
+	case "desktop_previous":
+		success = PreviousDesktop(tr, ws)
+	case "desktop_back_and_forth":
+		success = BackAndForthDesktop(tr, ws)
 	case "window_float_toggle":
 		success = ToggleWindowFloat(tr)
 	case "master_make":
@@ -111,7 +155,23 @@ func ExecuteAction(action string, tr *desktop.Tracker, ws *desktop.Workspace) bo
 	case "exit":
 		success = Exit(tr)
 	default:
-		success = External(action)
+		if IsMacro(action) {
+			success = ExecuteMacro(action, tr, ws)
+		} else if a, err := ParseAction(action); err != nil {
+			log.Warn("Error parsing action ", action, ": ", err)
+		} else if a != nil {
+			success = ExecuteParameterAction(a, tr, ws)
+		} else if desktop, ok := desktopIndex(action, "desktop_", ""); ok {
+			success = SwitchDesktop(tr, ws, desktop)
+		} else if desktop, ok := desktopIndex(action, "window_to_desktop_", "_follow"); ok {
+			success = WindowToDesktop(tr, ws, desktop, true)
+		} else if desktop, ok := desktopIndex(action, "window_to_desktop_", ""); ok {
+			success = WindowToDesktop(tr, ws, desktop, false)
+		} else if strings.HasPrefix(action, "mode_") {
+			success = EnableMode(tr, strings.TrimPrefix(action, "mode_"))
+		} else {
+			success = External(tr, ws, action)
+		}
 	}
 	time.AfterFunc(100*time.Millisecond, tr.Handlers.Reset)
 
@@ -126,6 +186,110 @@ func ExecuteAction(action string, tr *desktop.Tracker, ws *desktop.Workspace) bo
 	return true
 }
 
+func ExecuteParameterAction(a *Action, tr *desktop.Tracker, ws *desktop.Workspace) bool {
+	success := false
+
+	// Choose parameterized action command
+	switch a.Name {
+	case "layout":
+		success = SetLayout(tr, ws, a.Argument)
+	case "gap":
+		success = SetGap(tr, ws, a)
+	case "proportion":
+		success = SetProportion(tr, ws, a)
+	case "desktop":
+		success = SwitchDesktop(tr, ws, uint(relative(a, int(store.Workplace.CurrentDesktop)+1)-1))
+	case "master_count":
+		success = SetMasterCount(tr, ws, a)
+	case "slave_count":
+		success = SetSlaveCount(tr, ws, a)
+	case "column":
+		success = SetColumns(tr, ws, a)
+	case "focus":
+		success = FocusMatch(tr, ws, a)
+	case "script":
+		success = ExecuteScript(tr, ws, a.Argument)
+	}
+
+	return success
+}
+
+func ExecuteMacro(macro string, tr *desktop.Tracker, ws *desktop.Workspace) bool {
+	steps, err := ParseMacro(macro)
+	if err != nil {
+		log.Warn("Error parsing macro ", macro, ": ", err)
+		return false
+	}
+	success := executeSteps(steps, tr, ws)
+
+	// Report macros with delays as successful
+	for _, s := range steps {
+		if s.Delay > 0 {
+			return true
+		}
+	}
+
+	return success
+}
+
+func executeSteps(steps []*Step, tr *desktop.Tracker, ws *desktop.Workspace) bool {
+	success := false
+
+	// Execute steps until next delay and tile once
+	tr.Transaction(func() {
+		for len(steps) > 0 && steps[0].Delay == 0 {
+			s := steps[0]
+			steps = steps[1:]
+			if s.Condition != nil && !matchCondition(s.Condition, tr, ws) {
+				continue
+			}
+			if ExecuteAction(s.Action, tr, ws) {
+				success = true
+			}
+		}
+	})
+
+	// Continue remaining steps on the X event loop after delay
+	if len(steps) > 0 {
+		delay, remaining := steps[0].Delay, steps[1:]
+		time.AfterFunc(delay, func() {
+			store.Enqueue(func() {
+				executeSteps(remaining, tr, ws)
+			})
+		})
+	}
+
+	return success
+}
+
+func matchCondition(c *Condition, tr *desktop.Tracker, ws *desktop.Workspace) bool {
+	value := ""
+
+	// Obtain compared value
+	switch c.Field {
+	case "layout":
+		value = ws.ActiveLayout().GetName()
+	case "tiling":
+		value = "disabled"
+		if ws.TilingEnabled() {
+			value = "enabled"
+		}
+	case "class", "name":
+		if ac := tr.ActiveClient(); ac != nil {
+			value = ac.Latest.Class
+			if c.Field == "name" {
+				value = ac.Latest.Name
+			}
+		}
+	case "desktop":
+		value = strconv.Itoa(int(ws.Location.Desktop) + 1)
+	case "screen":
+		value = strconv.Itoa(int(ws.Location.Screen) + 1)
+	}
+
+	return c.Pattern.MatchString(value) != c.Negate
+}
+
 func ExecuteActions(action string, tr *desktop.Tracker, mod string) bool {
 	client := tr.ClientWorkspace(tr.ActiveClient())
 	active := tr.ActiveWorkspace()
@@ -466,6 +630,148 @@ func PreviousWindow(tr *desktop.Tracker, ws *desktop.Workspace) bool {
 	return true
 }
 
+func FocusLast(tr *desktop.Tracker, ws *desktop.Workspace) bool {
+	c := tr.RecentClient(nil)
+	if c == nil {
+		return false
+	}
+
+	store.ActiveWindowSet(store.X, c.Window)
+
+	return true
+}
+
+func FocusRecentNext(tr *desktop.Tracker, ws *desktop.Workspace) bool {
+	c := tr.CycleClient(ws, 1)
+	if c == nil {
+		return false
+	}
+
+	store.ActiveWindowSet(store.X, c.Window)
+
+	return true
+}
+
+func FocusRecentPrevious(tr *desktop.Tracker, ws *desktop.Workspace) bool {
+	c := tr.CycleClient(ws, -1)
+	if c == nil {
+		return false
+	}
+
+	store.ActiveWindowSet(store.X, c.Window)
+
+	return true
+}
+
+func FocusUrgent(tr *desktop.Tracker, ws *desktop.Workspace) bool {
+	clients := tr.UrgentClients()
+	if len(clients) == 0 {
+		return false
+	}
+
+	// Focus oldest urgent client
+	focusClient(clients[0])
+
+	return true
+}
+
+func FocusMatch(tr *desktop.Tracker, ws *desktop.Workspace, a *Action) bool {
+	clients := []*store.Client{}
+
+	// Obtain clients ordered by recent usage
+	for _, w := range tr.History.Windows(nil) {
+		if c, ok := tr.Clients[w]; ok {
+			clients = append(clients, c)
+		}
+	}
+	for _, c := range tr.Clients {
+		if indexClient(clients, c) < 0 {
+			clients = append(clients, c)
+		}
+	}
+
+	// Focus most recent matching client
+	for _, c := range clients {
+		value := c.Latest.Class
+		if a.Field == "name" {
+			value = c.Latest.Name
+		}
+		if a.Pattern.MatchString(value) {
+			focusClient(c)
+			return true
+		}
+	}
+
+	return false
+}
+
+func WindowHints(tr *desktop.Tracker, ws *desktop.Workspace) bool {
+	return showHints(tr, func(c *store.Client) {
+		active, geom := activeGeometry(tr)
+		store.ActiveWindowSet(store.X, c.Window)
+		warpPointer(tr, active, geom)
+	})
+}
+
+func WindowHintsSwap(tr *desktop.Tracker, ws *desktop.Workspace) bool {
+	c := ws.ActiveLayout().ActiveClient()
+	if c == nil {
+		return false
+	}
+
+	return showHints(tr, func(co *store.Client) {
+		if co.Window.Id == c.Window.Id {
+			return
+		}
+		tr.SwapClients(c, co)
+	})
+}
+
+func FocusDirection(tr *desktop.Tracker, ws *desktop.Workspace, d *store.Directions) bool {
+	c := ws.ActiveLayout().ActiveClient()
+	if c == nil {
+		return false
+	}
+
+	// Obtain nearest client in direction
+	co := tr.ClientInDirection(c, d)
+	if co == nil {
+		return false
+	}
+
+	store.ActiveWindowSet(store.X, co.Window)
+
+	return true
+}
+
+func SwapDirection(tr *desktop.Tracker, ws *desktop.Workspace, d *store.Directions) bool {
+	if ws.TilingDisabled() {
+		return false
+	}
+	c := ws.ActiveLayout().ActiveClient()
+	if c == nil {
+		return false
+	}
+
+	// Swap with nearest client in direction
+	co := tr.ClientInDirection(c, d)
+	if co != nil && tr.ClientWorkspace(co).TilingEnabled() {
+		tr.SwapClients(c, co)
+		return true
+	}
+	if !common.Config.WindowCrossScreens {
+		return false
+	}
+
+	// Move to adjacent screen without clients
+	screen, ok := store.ScreenNeighbor(c.Latest.Location.Screen, d)
+	if !ok {
+		return false
+	}
+
+	return c.MoveToScreen(uint32(screen))
+}
+
 func NextScreen(tr *desktop.Tracker, ws *desktop.Workspace) bool {
 	c := tr.ActiveClient()
 	if c == nil {
@@ -494,6 +800,282 @@ func PreviousScreen(tr *desktop.Tracker, ws *desktop.Workspace) bool {
 	return c.MoveToScreen(uint32(screen))
 }
 
+func SwapScreens(tr *desktop.Tracker, ws *desktop.Workspace) bool {
+	if store.Workplace.ScreenCount < 2 {
+		return false
+	}
+
+	// Swap with workspace on next screen
+	screen := (ws.Location.Screen + 1) % store.Workplace.ScreenCount
+	success := tr.SwapWorkspaces(ws, tr.WorkspaceAt(ws.Location.Desktop, screen))
+	if !success {
+		return false
+	}
+
+	ui.ShowLayout(ws)
+	ui.UpdateIcon(ws)
+
+	return true
+}
+
+func NextDesktop(tr *desktop.Tracker, ws *desktop.Workspace) bool {
+	count := store.Workplace.DesktopCount
+	if count < 2 {
+		return false
+	}
+
+	return SwitchDesktop(tr, ws, (store.Workplace.CurrentDesktop+1)%count)
+}
+
+func PreviousDesktop(tr *desktop.Tracker, ws *desktop.Workspace) bool {
+	count := store.Workplace.DesktopCount
+	if count < 2 {
+		return false
+	}
+
+	return SwitchDesktop(tr, ws, (store.Workplace.CurrentDesktop+count-1)%count)
+}
+
+func BackAndForthDesktop(tr *desktop.Tracker, ws *desktop.Workspace) bool {
+	desktop, ok := tr.History.PreviousDesktop()
+	if !ok {
+		return false
+	}
+
+	return SwitchDesktop(tr, ws, desktop)
+}
+
+func SwitchDesktop(tr *desktop.Tracker, ws *desktop.Workspace, desktop uint) bool {
+	if desktop >= store.Workplace.DesktopCount || desktop == store.Workplace.CurrentDesktop {
+		return false
+	}
+
+	store.CurrentDesktopSet(store.X, desktop)
+
+	return true
+}
+
+func WindowToDesktop(tr *desktop.Tracker, ws *desktop.Workspace, desktop uint, follow bool) bool {
+	c := tr.ActiveClient()
+	if c == nil || desktop >= store.Workplace.DesktopCount || desktop == c.Latest.Location.Desktop {
+		return false
+	}
+
+	// Move client, the workspace handler keeps its master/slave slot
+	if !c.MoveToDesktop(uint32(desktop)) {
+		return false
+	}
+
+	// Follow client to desktop
+	if follow {
+		store.CurrentDesktopSet(store.X, desktop)
+		store.ActiveWindowSet(store.X, c.Window)
+	}
+
+	return true
+}
+
+func relative(a *Action, value int) int {
+	if a.Relative {
+		return value + int(a.Number)
+	}
+	return int(a.Number)
+}
+
+func focusClient(c *store.Client) {
+
+	// Switch to desktop of client
+	if c.Latest.Location.Desktop != store.Workplace.CurrentDesktop && c.Latest.Location.Desktop < store.Workplace.DesktopCount {
+		store.CurrentDesktopSet(store.X, c.Latest.Location.Desktop)
+	}
+
+	store.ActiveWindowSet(store.X, c.Window)
+}
+
+func indexClient(clients []*store.Client, c *store.Client) int {
+	for i, ci := range clients {
+		if ci == c {
+			return i
+		}
+	}
+	return -1
+}
+
+func desktopIndex(action string, prefix string, suffix string) (uint, bool) {
+	if !strings.HasPrefix(action, prefix) || !strings.HasSuffix(action, suffix) {
+		return 0, false
+	}
+
+	// Parse one-based desktop number
+	n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(action, prefix), suffix))
+	if err != nil || n < 1 {
+		return 0, false
+	}
+
+	return uint(n - 1), true
+}
+
+func SetLayout(tr *desktop.Tracker, ws *desktop.Workspace, name string) bool {
+	if ws.TilingDisabled() {
+		return false
+	}
+
+	// Activate layout by name
+	if name == "autotile" {
+		return AutotileLayoutAction(tr, ws)
+	}
+	for i, l := range ws.Layouts {
+		if l.GetName() != name {
+			continue
+		}
+		ws.SetLayout(uint(i))
+		ws.ActiveLayout().ResetColumns()
+		tr.Tile(ws)
+
+		ui.ShowLayout(ws)
+		ui.UpdateIcon(ws)
+
+		return true
+	}
+
+	return false
+}
+
+func SetGap(tr *desktop.Tracker, ws *desktop.Workspace, a *Action) bool {
+	gap := common.MaxInt(relative(a, common.Config.WindowGapSize), 0)
+	if gap == common.Config.WindowGapSize {
+		return false
+	}
+	common.Config.WindowGapSize = gap
+
+	// Tile workspaces on current desktop
+	for _, w := range tr.Workspaces {
+		if w.Location.Desktop == ws.Location.Desktop && w.TilingEnabled() {
+			tr.Tile(w)
+		}
+	}
+
+	return true
+}
+
+func SetProportion(tr *desktop.Tracker, ws *desktop.Workspace, a *Action) bool {
+	if ws.TilingDisabled() {
+		return false
+	}
+	mg := ws.ActiveLayout().GetManager()
+
+	// Set root proportion
+	proportion := a.Number
+	if a.Relative {
+		proportion += mg.Proportions.MasterSlave[2][0]
+	}
+	if !mg.SetProportions(mg.Proportions.MasterSlave[2], proportion, 0, 1) {
+		return false
+	}
+	tr.Tile(ws)
+
+	return true
+}
+
+func SetMasterCount(tr *desktop.Tracker, ws *desktop.Workspace, a *Action) bool {
+	if ws.TilingDisabled() {
+		return false
+	}
+	al := ws.ActiveLayout()
+	mg := al.GetManager()
+
+	// Step master count towards target
+	previous := mg.Masters.Maximum
+	target := relative(a, previous)
+	for mg.Masters.Maximum != target {
+		maximum := mg.Masters.Maximum
+		if maximum < target {
+			al.IncreaseMaster()
+		} else {
+			al.DecreaseMaster()
+		}
+		if mg.Masters.Maximum == maximum {
+			break
+		}
+	}
+	if mg.Masters.Maximum == previous {
+		return false
+	}
+	tr.Tile(ws)
+
+	ui.ShowLayout(ws)
+	ui.UpdateIcon(ws)
+
+	return true
+}
+
+func SetSlaveCount(tr *desktop.Tracker, ws *desktop.Workspace, a *Action) bool {
+	if ws.TilingDisabled() {
+		return false
+	}
+	al := ws.ActiveLayout()
+	mg := al.GetManager()
+
+	// Step slave count towards target
+	previous := mg.Slaves.Maximum
+	target := relative(a, previous)
+	for mg.Slaves.Maximum != target {
+		maximum := mg.Slaves.Maximum
+		if maximum < target {
+			al.IncreaseSlave()
+		} else {
+			al.DecreaseSlave()
+		}
+		if mg.Slaves.Maximum == maximum {
+			break
+		}
+	}
+	if mg.Slaves.Maximum == previous {
+		return false
+	}
+	tr.Tile(ws)
+
+	ui.ShowLayout(ws)
+	ui.UpdateIcon(ws)
+
+	return true
+}
+
+func SetColumns(tr *desktop.Tracker, ws *desktop.Workspace, a *Action) bool {
+	if ws.TilingDisabled() {
+		return false
+	}
+
+	// Switch to autotile layout if not already in it
+	if ws.ActiveLayout().GetName() != "autotile" {
+		AutotileLayoutAction(tr, ws)
+	}
+	al, ok := ws.ActiveLayout().(*layout.AutotileLayout)
+	if !ok {
+		return false
+	}
+
+	// Step column count towards target
+	target := relative(a, al.Columns)
+	for al.Columns != target {
+		columns := al.Columns
+		if columns < target {
+			al.IncreaseColumn()
+		} else {
+			al.DecreaseColumn()
+		}
+		if al.Columns == columns {
+			break
+		}
+	}
+	tr.Tile(ws)
+
+	ui.ShowLayout(ws)
+	ui.UpdateIcon(ws)
+
+	return true
+}
+
 // ToggleWindowFloat alterna la ventana activa entre el tiling y el estado flotante.
 // Usa FloatedWindows del Tracker para no depender de estados EWMH externos.
 // Obtiene el ID de la ventana directamente del X server para funcionar
@@ -597,9 +1179,15 @@ func Restart(tr *desktop.Tracker) bool {
 
 	log.Info("Restart")
 
+	// Execute callbacks
+	executeCallbacks("restart", store.Workplace.CurrentDesktop, store.Workplace.CurrentScreen)
+
 	// Communicate application exit
 	Disconnect()
 
+	// Terminate addon processes
+	StopAddons()
+
 	// Restart application
 	syscall.Exec(common.Process.Path, os.Args, os.Environ())
 
@@ -621,31 +1209,74 @@ func Exit(tr *desktop.Tracker) bool {
 
 	log.Info("Exit")
 
+	// Execute callbacks
+	executeCallbacks("exit", store.Workplace.CurrentDesktop, store.Workplace.CurrentScreen)
+
 	// Communicate application exit
 	Disconnect()
 
+	// Terminate addon processes
+	StopAddons()
+
 	// Exit application
 	os.Exit(0)
 
 	return true
 }
 
-func External(command string) bool {
-	params := strings.Split(command, " ")
+func External(tr *desktop.Tracker, ws *desktop.Workspace, command string) bool {
+	params, err := ParseCommand(command)
+	if err != nil {
+		log.Warn("Error parsing external command ", command, ": ", err)
+		return false
+	}
 
 	if !common.HasFlag("enable-external-commands") {
 		log.Warn("Executing external command \"", params[0], "\" disabled")
 		return false
 	}
 
-	log.Info("Executing external command \"", params[0], " ", params[1:], "\"")
+	// Obtain command context
+	env := append([]string{
+		"CORTILE_EVENT=external",
+		fmt.Sprintf("CORTILE_ACTION=%s", command),
+	}, contextEnv(ws, store.Windows.Active.Id, tr.ActiveClient())...)
 
-	// Execute external command
+	// Execute external command asynchronously in own process group
 	cmd := exec.Command(params[0], params[1:]...)
-	if err := cmd.Run(); err != nil {
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
 		log.Error("External command failed: ", err)
 		return false
 	}
+	pid := cmd.Process.Pid
+	log.Info("Executing external command \"", params[0], " ", params[1:], "\" [", pid, "]")
+
+	// Terminate process group after timeout
+	var timer *time.Timer
+	if common.Config.ExternalTimeout > 0 {
+		timer = time.AfterFunc(time.Duration(common.Config.ExternalTimeout)*time.Second, func() {
+			log.Warn("External command \"", params[0], "\" [", pid, "] timed out")
+			syscall.Kill(-pid, syscall.SIGKILL)
+		})
+	}
+
+	// Release process resources
+	go func() {
+		started := time.Now()
+		err := cmd.Wait()
+		if timer != nil {
+			timer.Stop()
+		}
+		if err != nil {
+			log.Warn("External command \"", params[0], "\" [", pid, "] failed after ", time.Since(started), ": ", err)
+			return
+		}
+		log.Info("External command \"", params[0], "\" [", pid, "] finished after ", time.Since(started))
+	}()
 
 	return true
 }
@@ -1,48 +1,406 @@
 package input
 
 import (
+	"bufio"
+	"fmt"
+	"io"
 	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
+	"encoding/json"
 	"os/exec"
 	"path/filepath"
 
 	"github.com/leukipp/cortile/v2/common"
 	"github.com/leukipp/cortile/v2/desktop"
+	"github.com/leukipp/cortile/v2/store"
 
 	log "github.com/sirupsen/logrus"
 )
 
+var (
+	addons     []*Addon   // Supervised addon processes
+	addonsOnce sync.Once  // Read addons folder only once
+	addonsMu   sync.Mutex // Lock for addon state updates
+)
+
+var (
+	addonBackoffMin = time.Second           // Initial delay between addon restarts
+	addonBackoffMax = time.Minute           // Maximum delay between addon restarts
+	addonStable     = time.Minute           // Running time after which the delay is reset
+	addonTimeout    = 2 * time.Second       // Time to wait for addons to terminate
+	addonPoll       = 50 * time.Millisecond // Interval to check for terminated addons
+)
+
+type Addon struct {
+	Name     string        // Addon file name
+	Path     string        // Addon file path
+	Log      string        // Addon log file path
+	Status   string        // Addon status (starting, running, restarting, stopped)
+	Pid      int           // Process id of running addon
+	Restarts int           // Number of addon restarts
+	Error    string        // Last addon exit error
+	Stdio    bool          // Addon uses stdio protocol
+	events   chan string   // Channel of events written to stdio addon
+	stopped  bool          // Addon is terminated on exit
+	restart  bool          // Addon is restarted immediately
+	wakeup   chan struct{} // Channel to interrupt restart delays
+}
+
 func BindAddons(tr *desktop.Tracker) {
-	if common.HasFlag("disable-dbus-interface") || common.HasFlag("disable-addons-folder") {
+
+	// Supervise addon processes
+	for _, a := range Addons() {
+		go supervise(a, tr)
+	}
+}
+
+func Addons() []*Addon {
+	addonsOnce.Do(func() {
+		addons = readAddons()
+	})
+	return addons
+}
+
+func RestartAddon(a *Addon) {
+	addonsMu.Lock()
+	defer addonsMu.Unlock()
+	if a.stopped {
 		return
 	}
+	log.Info("Restart addon ", a.Name)
+
+	// Terminate process and skip restart delay
+	a.restart = true
+	if a.Pid > 0 {
+		syscall.Kill(-a.Pid, syscall.SIGTERM)
+	}
+	select {
+	case a.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func StopAddons() {
+	addonsMu.Lock()
+	for _, a := range Addons() {
+		a.stopped = true
+
+		// Terminate process group of addon
+		if a.Pid > 0 {
+			log.Info("Terminate addon ", a.Name)
+			syscall.Kill(-a.Pid, syscall.SIGTERM)
+		}
+		select {
+		case a.wakeup <- struct{}{}:
+		default:
+		}
+	}
+	addonsMu.Unlock()
+
+	// Kill addons that are still running after timeout
+	deadline := time.Now().Add(addonTimeout)
+	for time.Now().Before(deadline) && runningAddons() > 0 {
+		time.Sleep(addonPoll)
+	}
+	addonsMu.Lock()
+	for _, a := range Addons() {
+		if a.Pid > 0 {
+			log.Warn("Kill addon ", a.Name)
+			syscall.Kill(-a.Pid, syscall.SIGKILL)
+		}
+	}
+	addonsMu.Unlock()
+}
+
+func readAddons() []*Addon {
+	list := []*Addon{}
+	if common.HasFlag("disable-addons-folder") {
+		return list
+	}
 
 	// Check if addons folder exists
 	configFolderPath := common.ConfigFolderPath(common.Build.Name)
 	addonsFolderPath := filepath.Join(configFolderPath, "addons")
 	if _, err := os.Stat(addonsFolderPath); os.IsNotExist(err) {
-		return
+		return list
 	}
 
 	// Read files in addons folder
 	files, err := os.ReadDir(addonsFolderPath)
 	if err != nil {
 		log.Warn("Error reading addons: ", addonsFolderPath)
-		return
+		return list
 	}
 
-	// Run files in addons folder
+	// Collect executable files in addons folder
+	logPath := strings.TrimSuffix(common.Args.Log, filepath.Ext(common.Args.Log))
 	for _, file := range files {
 		addonFilePath := filepath.Join(addonsFolderPath, file.Name())
-		log.Info("Execute addon ", addonFilePath)
+		if strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+		stdio := strings.HasSuffix(file.Name(), ".stdio")
+		if !stdio && common.HasFlag("disable-dbus-interface") {
+			log.Info("Skip dbus addon ", addonFilePath)
+			continue
+		}
+		info, err := os.Stat(addonFilePath)
+		if err != nil || info.IsDir() || info.Mode().Perm()&0111 == 0 {
+			log.Info("Skip non executable addon ", addonFilePath)
+			continue
+		}
+		list = append(list, &Addon{
+			Name:   file.Name(),
+			Path:   addonFilePath,
+			Log:    fmt.Sprintf("%s.%s.log", logPath, file.Name()),
+			Status: "stopped",
+			Stdio:  stdio,
+			wakeup: make(chan struct{}, 1),
+		})
+	}
+
+	return list
+}
+
+func supervise(a *Addon, tr *desktop.Tracker) {
+	backoff := addonBackoffMin
+	for {
+		started := time.Now()
+
+		// Reset restart request
+		addonsMu.Lock()
+		a.restart = false
+		a.Status = "starting"
+		addonsMu.Unlock()
+
+		// Run addon until it exits
+		err := run(a, tr)
+
+		// Update exit state
+		addonsMu.Lock()
+		a.Pid = 0
+		a.Error = ""
+		if err != nil {
+			a.Error = err.Error()
+		}
+		if a.stopped {
+			a.Status = "stopped"
+			addonsMu.Unlock()
+			updateAddons()
+			return
+		}
+		if err == nil && !a.restart {
+			a.Status = "stopped"
+			addonsMu.Unlock()
+			updateAddons()
+			log.Info("Addon ", a.Name, " finished")
+
+			// Wait for manual restart of finished addon
+			<-a.wakeup
+			backoff = addonBackoffMin
+			continue
+		}
+		restart := a.restart
+		a.Status = "restarting"
+		a.Restarts += 1
+		addonsMu.Unlock()
+		updateAddons()
+
+		// Reset delay of stable or manually restarted addons
+		if restart || time.Since(started) > addonStable {
+			backoff = addonBackoffMin
+		}
+		if !restart {
+			log.Warn("Addon ", a.Name, " exited (", err, "), restart in ", backoff)
+		}
+
+		// Wait before restarting addon
+		select {
+		case <-a.wakeup:
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, addonBackoffMax)
+	}
+}
 
-		// Execute addon scripts
-		addon := exec.Command(addonFilePath)
-		addon.Stdout = os.Stdout
-		addon.Stderr = os.Stderr
+func run(a *Addon, tr *desktop.Tracker) error {
+	log.Info("Execute addon ", a.Path)
 
-		if err = addon.Start(); err != nil {
-			log.Warn("Error executing addon: ", err)
+	// Open addon log file
+	file, err := os.OpenFile(a.Log, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	fmt.Fprintf(file, "[%s] start %s\n", time.Now().Format(time.RFC3339), a.Path)
+
+	// Execute addon in own process group
+	cmd := exec.Command(a.Path)
+	cmd.Stdout = file
+	cmd.Stderr = file
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	// Connect stdio protocol pipes
+	var stdin io.WriteCloser
+	var stdout io.ReadCloser
+	if a.Stdio {
+		cmd.Stdout = nil
+		if stdin, err = cmd.StdinPipe(); err != nil {
+			return err
+		}
+		if stdout, err = cmd.StdoutPipe(); err != nil {
+			return err
+		}
+	}
+
+	addonsMu.Lock()
+	if a.stopped {
+		addonsMu.Unlock()
+		return nil
+	}
+	err = cmd.Start()
+	if err == nil {
+		a.Pid = cmd.Process.Pid
+		a.Status = "running"
+	}
+	addonsMu.Unlock()
+	if err != nil {
+		fmt.Fprintf(file, "[%s] error %s\n", time.Now().Format(time.RFC3339), err)
+		return err
+	}
+	updateAddons()
+
+	// Exchange events and requests with addon
+	if a.Stdio {
+		events := make(chan string, 256)
+		done := make(chan struct{})
+
+		addonsMu.Lock()
+		a.events = events
+		addonsMu.Unlock()
+
+		go writeEvents(stdin, events, done)
+		readRequests(a, stdout, events, tr)
+
+		addonsMu.Lock()
+		a.events = nil
+		addonsMu.Unlock()
+		close(done)
+	}
+
+	// Wait for addon exit
+	err = cmd.Wait()
+	fmt.Fprintf(file, "[%s] exit %v\n", time.Now().Format(time.RFC3339), err)
+
+	return err
+}
+
+func writeEvents(stdin io.WriteCloser, events chan string, done chan struct{}) {
+	defer stdin.Close()
+
+	// Write events line by line, discard them if addon stopped reading
+	closed := false
+	for {
+		select {
+		case line := <-events:
+			if closed {
+				continue
+			}
+			if _, err := io.WriteString(stdin, line); err != nil {
+				closed = true
+			}
+		case <-done:
+			return
 		}
 	}
 }
+
+func readRequests(a *Addon, stdout io.Reader, events chan string, tr *desktop.Tracker) {
+	reader := bufio.NewScanner(stdout)
+
+	// Read requests line by line
+	for reader.Scan() {
+		line := strings.TrimSpace(reader.Text())
+		if len(line) == 0 {
+			continue
+		}
+		log.Info("Addon request ", a.Name, " [", line, "]")
+
+		// Parse json request or plain action
+		var r Request
+		if strings.HasPrefix(line, "{") {
+			if err := json.Unmarshal([]byte(line), &r); err != nil {
+				sendEvent(a, events, dataMap("Error", "Request", common.Map{"Message": err.Error()})+"\n")
+				continue
+			}
+		} else {
+			r = Request{Command: "action", Args: []string{line}}
+		}
+
+		// Reply to request executed on the X event loop
+		var result string
+		store.Execute(func() {
+			result = reply(r, tr)
+		})
+		sendEvent(a, events, result)
+	}
+}
+
+func sendEvent(a *Addon, events chan string, line string) {
+
+	// Drop lines if addon stopped reading
+	select {
+	case events <- line:
+	default:
+		log.Warn("Drop event for addon ", a.Name)
+	}
+}
+
+func addonStreams() []chan string {
+	addonsMu.Lock()
+	defer addonsMu.Unlock()
+
+	// Obtain event channels of running stdio addons
+	streams := []chan string{}
+	for _, a := range Addons() {
+		if a.events != nil {
+			streams = append(streams, a.events)
+		}
+	}
+
+	return streams
+}
+
+func runningAddons() int {
+	addonsMu.Lock()
+	defer addonsMu.Unlock()
+
+	// Count running addon processes
+	count := 0
+	for _, a := range Addons() {
+		if a.Pid > 0 {
+			count += 1
+		}
+	}
+
+	return count
+}
+
+func updateAddons() {
+	list := Addons()
+
+	addonsMu.Lock()
+	values := make([]Addon, len(list))
+	for i, a := range list {
+		values[i] = *a
+	}
+	addonsMu.Unlock()
+
+	// Communicate addon states
+	SetProperty("Addons", common.Map{"Values": values})
+	updateAddonItems(values)
+}
@@ -2,8 +2,10 @@ package input
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"reflect"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -23,17 +25,39 @@ import (
 	"github.com/leukipp/cortile/v2/common"
 	"github.com/leukipp/cortile/v2/desktop"
 	"github.com/leukipp/cortile/v2/store"
+	"github.com/leukipp/cortile/v2/ui"
 
 	log "github.com/sirupsen/logrus"
 )
 
 var (
-	iface   string           // Dbus interface name
-	opath   dbus.ObjectPath  // Dbus object path
-	props   *prop.Properties // Dbus properties
-	methods *Methods         // Dbus methods
+	iface    string           // Dbus interface name
+	opath    dbus.ObjectPath  // Dbus object path
+	bus      *dbus.Conn       // Dbus connection
+	props    *prop.Properties // Dbus properties
+	methods  *Methods         // Dbus methods
+	snapshot *Snapshot        // Dbus state for signal comparison
 )
 
+var (
+	signals = []introspect.Signal{ // Dbus signals
+		{Name: "LayoutChanged", Args: []introspect.Arg{{Name: "desktop", Type: "i"}, {Name: "screen", Type: "i"}, {Name: "name", Type: "s"}}},
+		{Name: "ClientAdded", Args: []introspect.Arg{{Name: "id", Type: "i"}, {Name: "class", Type: "s"}}},
+		{Name: "ClientRemoved", Args: []introspect.Arg{{Name: "id", Type: "i"}}},
+		{Name: "FocusChanged", Args: []introspect.Arg{{Name: "id", Type: "i"}}},
+		{Name: "TilingToggled", Args: []introspect.Arg{{Name: "desktop", Type: "i"}, {Name: "screen", Type: "i"}, {Name: "enabled", Type: "b"}}},
+		{Name: "ProportionsChanged", Args: []introspect.Arg{{Name: "desktop", Type: "i"}, {Name: "screen", Type: "i"}, {Name: "master_slave", Type: "ad"}, {Name: "master_master", Type: "ad"}, {Name: "slave_slave", Type: "ad"}}},
+	}
+)
+
+type Snapshot struct {
+	Clients     map[xproto.Window]bool      // Tracked client windows
+	Layouts     map[store.Location]string   // Active layout names
+	Tilings     map[store.Location]bool     // Tiling enabled states
+	Proportions map[store.Location][]string // Active layout proportions
+	Focused     xproto.Window               // Active client window
+}
+
 type Methods struct {
 	Naming  map[string][]string // Method and arguments names
 	Tracker *desktop.Tracker    // Workspace tracker instance
@@ -42,6 +66,13 @@ type Methods struct {
 func (m Methods) ActionExecute(name string, desktop int32, screen int32) (string, *dbus.Error) {
 	success := false
 
+	// Validate action
+	err := ValidateAction(name)
+	if err != nil {
+		result := common.Map{"Success": success, "Error": err.Error()}
+		return dataMap("Result", "ActionExecute", result), nil
+	}
+
 	// Execute action
 	ws := m.Tracker.WorkspaceAt(uint(desktop), uint(screen))
 	if ws != nil {
@@ -132,6 +163,280 @@ func (m Methods) DesktopSwitch(desktop int32) (string, *dbus.Error) {
 	return dataMap("Result", "DesktopSwitch", result), nil
 }
 
+func (m Methods) WorkspaceSwapScreens(desktop int32, screen1 int32, screen2 int32) (string, *dbus.Error) {
+	success := false
+
+	// Swap workspaces between screens
+	valid := screen1 >= 0 && screen2 >= 0 && uint(screen1) < store.Workplace.ScreenCount && uint(screen2) < store.Workplace.ScreenCount
+	if valid {
+		ws1 := m.Tracker.WorkspaceAt(uint(desktop), uint(screen1))
+		ws2 := m.Tracker.WorkspaceAt(uint(desktop), uint(screen2))
+		success = m.Tracker.SwapWorkspaces(ws1, ws2)
+	}
+
+	// Return result
+	result := common.Map{"Success": success}
+
+	return dataMap("Result", "WorkspaceSwapScreens", result), nil
+}
+
+func (m Methods) WindowFloat(id int32, float bool) (string, *dbus.Error) {
+	success := false
+
+	// Toggle window float state of tracked or floated window
+	w := xproto.Window(id)
+	if _, ok := m.Tracker.Clients[w]; ok || m.Tracker.FloatedWindows[w] {
+		if m.Tracker.FloatedWindows[w] != float {
+			m.Tracker.ToggleFloat(w)
+		}
+		success = true
+	}
+
+	// Return result
+	result := common.Map{"Success": success}
+
+	return dataMap("Result", "WindowFloat", result), nil
+}
+
+func (m Methods) WindowMakeMaster(id int32) (string, *dbus.Error) {
+	success := false
+
+	// Make window master
+	if c, ok := m.Tracker.Clients[xproto.Window(id)]; ok {
+		ws := m.Tracker.ClientWorkspace(c)
+		if ws != nil && ws.TilingEnabled() {
+			ws.ActiveLayout().MakeMaster(c)
+			m.Tracker.Tile(ws)
+			success = true
+		}
+	}
+
+	// Return result
+	result := common.Map{"Success": success}
+
+	return dataMap("Result", "WindowMakeMaster", result), nil
+}
+
+func (m Methods) WindowSwap(id1 int32, id2 int32) (string, *dbus.Error) {
+	success := false
+
+	// Swap window positions
+	c1, ok1 := m.Tracker.Clients[xproto.Window(id1)]
+	c2, ok2 := m.Tracker.Clients[xproto.Window(id2)]
+	if ok1 && ok2 && c1 != c2 {
+		ws1, ws2 := m.Tracker.ClientWorkspace(c1), m.Tracker.ClientWorkspace(c2)
+		if ws1 != nil && ws2 != nil && ws1.TilingEnabled() && ws2.TilingEnabled() {
+			m.Tracker.SwapClients(c1, c2)
+			success = true
+		}
+	}
+
+	// Return result
+	result := common.Map{"Success": success}
+
+	return dataMap("Result", "WindowSwap", result), nil
+}
+
+func (m Methods) WindowDecorate(id int32, enabled bool) (string, *dbus.Error) {
+	success := false
+
+	// Override window decoration on tiled workspace
+	if c, ok := m.Tracker.Clients[xproto.Window(id)]; ok {
+		if ws := m.Tracker.ClientWorkspace(c); ws != nil && ws.TilingEnabled() {
+			c.Decoration = &enabled
+			m.Tracker.Tile(ws)
+			success = true
+		}
+	}
+
+	// Return result
+	result := common.Map{"Success": success}
+
+	return dataMap("Result", "WindowDecorate", result), nil
+}
+
+func (m Methods) SetLayout(desktop int32, screen int32, name string) (string, *dbus.Error) {
+	success := false
+
+	// Activate layout by name
+	if ws := m.workspace(desktop, screen); ws != nil {
+		success = SetLayout(m.Tracker, ws, name)
+	}
+
+	// Return result
+	result := common.Map{"Success": success}
+
+	return dataMap("Result", "SetLayout", result), nil
+}
+
+func (m Methods) SetProportions(desktop int32, screen int32, kind string, values []float64) (string, *dbus.Error) {
+	success := false
+
+	// Validate workspace
+	ws := m.workspace(desktop, screen)
+	if ws == nil {
+		result := common.Map{"Success": success, "Error": fmt.Sprintf("invalid workspace %d-%d", desktop, screen)}
+		return dataMap("Result", "SetProportions", result), nil
+	}
+
+	// Update proportions
+	if err := setProportions(m.Tracker, ws, kind, values); err != nil {
+		result := common.Map{"Success": success, "Error": err.Error()}
+		return dataMap("Result", "SetProportions", result), nil
+	}
+	success = true
+
+	// Return result
+	result := common.Map{"Success": success}
+
+	return dataMap("Result", "SetProportions", result), nil
+}
+
+func (m Methods) SetMasterCount(desktop int32, screen int32, count int32) (string, *dbus.Error) {
+	success := false
+
+	// Set maximum number of masters
+	if ws := m.workspace(desktop, screen); ws != nil && count >= 0 {
+		success = SetMasterCount(m.Tracker, ws, &Action{Number: float64(count)})
+	}
+
+	// Return result
+	result := common.Map{"Success": success}
+
+	return dataMap("Result", "SetMasterCount", result), nil
+}
+
+func (m Methods) SetSlaveCount(desktop int32, screen int32, count int32) (string, *dbus.Error) {
+	success := false
+
+	// Set maximum number of slaves
+	if ws := m.workspace(desktop, screen); ws != nil && count >= 0 {
+		success = SetSlaveCount(m.Tracker, ws, &Action{Number: float64(count)})
+	}
+
+	// Return result
+	result := common.Map{"Success": success}
+
+	return dataMap("Result", "SetSlaveCount", result), nil
+}
+
+func (m Methods) SetTiling(desktop int32, screen int32, enabled bool) (string, *dbus.Error) {
+	success := false
+
+	// Enable or disable tiling
+	if ws := m.workspace(desktop, screen); ws != nil {
+		if enabled {
+			success = EnableTiling(m.Tracker, ws)
+		} else {
+			success = DisableTiling(m.Tracker, ws)
+		}
+	}
+
+	// Return result
+	result := common.Map{"Success": success}
+
+	return dataMap("Result", "SetTiling", result), nil
+}
+
+func (m Methods) GetWorkspace(desktop int32, screen int32) (WorkspaceInfo, *dbus.Error) {
+
+	// Obtain workspace
+	ws := m.workspace(desktop, screen)
+	if ws == nil {
+		return WorkspaceInfo{}, dbus.MakeFailedError(fmt.Errorf("invalid workspace %d-%d", desktop, screen))
+	}
+
+	return workspaceInfo(ws), nil
+}
+
+func (m Methods) ListClients() ([]ClientInfo, *dbus.Error) {
+	return clientInfos(m.Tracker), nil
+}
+
+func (m Methods) GetClient(id int32) (ClientInfo, *dbus.Error) {
+
+	// Obtain client or floated window
+	c, ok := m.Tracker.Clients[xproto.Window(id)]
+	if !ok {
+		if info, ok := floatedInfo(xproto.Window(id), m.Tracker); ok {
+			return info, nil
+		}
+		return ClientInfo{}, dbus.MakeFailedError(fmt.Errorf("invalid client %d", id))
+	}
+
+	return clientInfo(c, m.Tracker), nil
+}
+
+func (m Methods) GetLayout(desktop int32, screen int32) (LayoutInfo, *dbus.Error) {
+
+	// Obtain workspace
+	ws := m.workspace(desktop, screen)
+	if ws == nil {
+		return LayoutInfo{}, dbus.MakeFailedError(fmt.Errorf("invalid workspace %d-%d", desktop, screen))
+	}
+
+	return layoutInfo(ws), nil
+}
+
+func (m Methods) GetFocused() (ClientInfo, *dbus.Error) {
+
+	// Obtain active client, an id of 0 indicates no tracked client is focused
+	c := m.Tracker.ActiveClient()
+	if c == nil {
+		return ClientInfo{}, nil
+	}
+
+	return clientInfo(c, m.Tracker), nil
+}
+
+func (m Methods) workspace(desktop int32, screen int32) *desktop.Workspace {
+	if desktop < 0 || screen < 0 {
+		return nil
+	}
+	return m.Tracker.Workspaces[store.Location{Desktop: uint(desktop), Screen: uint(screen)}]
+}
+
+func setProportions(tr *desktop.Tracker, ws *desktop.Workspace, kind string, values []float64) error {
+	if ws.TilingDisabled() {
+		return fmt.Errorf("tiling disabled on workspace %s", ws.Name)
+	}
+	mg := ws.ActiveLayout().GetManager()
+
+	// Obtain proportions of visible clients
+	var ps []float64
+	switch kind {
+	case "master_slave":
+		ps = mg.Proportions.MasterSlave[2]
+	case "master_master":
+		ps = mg.Proportions.MasterMaster[common.MinInt(len(mg.Masters.Stacked), mg.Masters.Maximum)]
+	case "slave_slave":
+		ps = mg.Proportions.SlaveSlave[common.MinInt(len(mg.Slaves.Stacked), mg.Slaves.Maximum)]
+	default:
+		return fmt.Errorf("invalid kind %s", kind)
+	}
+	if len(values) != len(ps) {
+		return fmt.Errorf("expected %d proportion values", len(ps))
+	}
+
+	// Validate minimum proportion and total sum
+	sum := 0.0
+	for _, v := range values {
+		if v < common.Config.ProportionMin {
+			return fmt.Errorf("proportion %g below minimum %g", v, common.Config.ProportionMin)
+		}
+		sum += v
+	}
+	if math.Abs(sum-1.0) > 1e-6 {
+		return fmt.Errorf("proportions sum %g not equal to 1", sum)
+	}
+
+	// Update proportions
+	copy(ps, values)
+	tr.Tile(ws)
+
+	return nil
+}
+
 func (m Methods) Introspection() []introspect.Method {
 	typ := reflect.TypeOf(m)
 	ims := make([]introspect.Method, 0, typ.NumMethod())
@@ -162,8 +467,12 @@ func (m Methods) Introspection() []introspect.Method {
 
 		// Arguments out
 		for j := 0; j < mt.NumOut()-1; j++ {
+			name := "json"
+			if mt.Out(j).Kind() != reflect.String {
+				name = "result"
+			}
 			styp := dbus.SignatureOfType(mt.Out(j)).String()
-			im.Args = append(im.Args, introspect.Arg{Name: "json", Type: styp, Direction: "out"})
+			im.Args = append(im.Args, introspect.Arg{Name: name, Type: styp, Direction: "out"})
 		}
 
 		ims = append(ims, im)
@@ -206,16 +515,29 @@ func BindDbus(tr *desktop.Tracker) {
 }
 
 func event(ch chan string, tr *desktop.Tracker) {
+	snapshot = &Snapshot{
+		Clients:     make(map[xproto.Window]bool),
+		Layouts:     make(map[store.Location]string),
+		Tilings:     make(map[store.Location]bool),
+		Proportions: make(map[store.Location][]string),
+	}
 	for {
 		switch <-ch {
 		case "clients_change":
 			SetProperty("Clients", common.Map{"Values": maps.Values(tr.Clients)})
+			emitClients(tr)
 		case "workspaces_change":
 			SetProperty("Workspaces", common.Map{"Values": maps.Values(tr.Workspaces)})
+			emitWorkspaces(tr)
 		case "workplace_change":
 			SetProperty("Workplace", *store.Workplace)
 		case "windows_change":
 			SetProperty("Windows", *store.Windows)
+			emitFocus(tr)
+		case "urgency_change":
+			urgent := tr.UrgentClients()
+			SetProperty("Urgent", common.Map{"Values": urgent})
+			ui.UpdateUrgent(tr.ActiveWorkspace(), len(urgent))
 		case "corner_change":
 			for _, hc := range store.Workplace.Displays.Corners {
 				if !hc.Active {
@@ -233,6 +555,79 @@ func event(ch chan string, tr *desktop.Tracker) {
 	}
 }
 
+func emitClients(tr *desktop.Tracker) {
+
+	// Emit added clients
+	for w, c := range tr.Clients {
+		if !snapshot.Clients[w] {
+			snapshot.Clients[w] = true
+			Emit("ClientAdded", int32(w), c.Latest.Class)
+		}
+	}
+
+	// Emit removed clients
+	for w := range snapshot.Clients {
+		if _, ok := tr.Clients[w]; !ok {
+			delete(snapshot.Clients, w)
+			Emit("ClientRemoved", int32(w))
+		}
+	}
+}
+
+func emitWorkspaces(tr *desktop.Tracker) {
+	for loc, ws := range tr.Workspaces {
+		desktop, screen := int32(loc.Desktop), int32(loc.Screen)
+
+		// Emit layout changes
+		name := ws.ActiveLayout().GetName()
+		if layout, ok := snapshot.Layouts[loc]; !ok || layout != name {
+			snapshot.Layouts[loc] = name
+			Emit("LayoutChanged", desktop, screen, name)
+		}
+
+		// Emit tiling changes
+		enabled := ws.TilingEnabled()
+		if tiling, ok := snapshot.Tilings[loc]; !ok || tiling != enabled {
+			snapshot.Tilings[loc] = enabled
+			Emit("TilingToggled", desktop, screen, enabled)
+		}
+
+		// Emit proportion changes
+		mg := ws.ActiveLayout().GetManager()
+		msize := common.MinInt(len(mg.Masters.Stacked), mg.Masters.Maximum)
+		ssize := common.MinInt(len(mg.Slaves.Stacked), mg.Slaves.Maximum)
+		ps := [][]float64{mg.Proportions.MasterSlave[2], mg.Proportions.MasterMaster[msize], mg.Proportions.SlaveSlave[ssize]}
+		proportions := []string{fmt.Sprint(ps[0]), fmt.Sprint(ps[1]), fmt.Sprint(ps[2])}
+		if !slices.Equal(snapshot.Proportions[loc], proportions) {
+			snapshot.Proportions[loc] = proportions
+			Emit("ProportionsChanged", desktop, screen, ps[0], ps[1], ps[2])
+		}
+	}
+}
+
+func emitFocus(tr *desktop.Tracker) {
+	c := tr.ActiveClient()
+	if c == nil || c.Window.Id == snapshot.Focused {
+		return
+	}
+
+	// Emit focus changes
+	snapshot.Focused = c.Window.Id
+	Emit("FocusChanged", int32(c.Window.Id))
+}
+
+func Emit(name string, values ...interface{}) {
+	if bus == nil {
+		return
+	}
+
+	// Emit dbus signal
+	err := bus.Emit(opath, fmt.Sprintf("%s.%s", iface, name), values...)
+	if err != nil {
+		log.Warn("Error emitting dbus signal ", name, ": ", err)
+	}
+}
+
 func connect() (*dbus.Conn, error) {
 	hostname := strings.Join(common.ReverseList(strings.Split(common.Source.Hostname, ".")), ".")
 	repository := strings.Replace(common.Source.Repository, "/", ".", -1)
@@ -274,6 +669,8 @@ func export(tr *desktop.Tracker) {
 		"Pointer":       common.Map{},
 		"Action":        common.Map{},
 		"Corner":        common.Map{},
+		"Urgent":        common.Map{},
+		"Addons":        common.Map{},
 		"Disconnect":    common.Map{},
 	}
 	properties := map[string]*prop.Prop{}
@@ -284,21 +681,40 @@ func export(tr *desktop.Tracker) {
 			Writable: len(value.(common.Map)) == 0,
 		}
 	}
+	properties["ApiVersion"] = &prop.Prop{
+		Value:    ApiVersion,
+		Emit:     prop.EmitConst,
+		Writable: false,
+	}
 	props, err = prop.Export(conn, opath, prop.Map{iface: properties})
 	if err != nil {
 		log.Warn("Error exporting dbus properties: ", err)
 		return
 	}
+	updateAddons()
 
 	// Export dbus methods
 	methods = &Methods{
 		Naming: map[string][]string{
-			"ActionExecute":    {"name", "desktop", "screen"},
-			"WindowActivate":   {"id"},
-			"WindowToPosition": {"id", "x", "y"},
-			"WindowToDesktop":  {"id", "desktop"},
-			"WindowToScreen":   {"id", "screen"},
-			"DesktopSwitch":    {"desktop"},
+			"ActionExecute":        {"name", "desktop", "screen"},
+			"WindowActivate":       {"id"},
+			"WindowToPosition":     {"id", "x", "y"},
+			"WindowToDesktop":      {"id", "desktop"},
+			"WindowToScreen":       {"id", "screen"},
+			"DesktopSwitch":        {"desktop"},
+			"WorkspaceSwapScreens": {"desktop", "screen1", "screen2"},
+			"WindowFloat":          {"id", "float"},
+			"WindowMakeMaster":     {"id"},
+			"WindowSwap":           {"id1", "id2"},
+			"WindowDecorate":       {"id", "enabled"},
+			"SetLayout":            {"desktop", "screen", "name"},
+			"SetProportions":       {"desktop", "screen", "kind", "values"},
+			"SetMasterCount":       {"desktop", "screen", "count"},
+			"SetSlaveCount":        {"desktop", "screen", "count"},
+			"SetTiling":            {"desktop", "screen", "enabled"},
+			"GetWorkspace":         {"desktop", "screen"},
+			"GetClient":            {"id"},
+			"GetLayout":            {"desktop", "screen"},
 		},
 		Tracker: tr,
 	}
@@ -318,6 +734,7 @@ func export(tr *desktop.Tracker) {
 				Name:       iface,
 				Methods:    methods.Introspection(),
 				Properties: props.Introspection(iface),
+				Signals:    signals,
 			},
 		},
 	})
@@ -327,6 +744,9 @@ func export(tr *desktop.Tracker) {
 		return
 	}
 
+	// Enable dbus signals
+	bus = conn
+
 	select {}
 }
 
@@ -346,6 +766,7 @@ func Introspect() map[string][]string {
 	// Iterate node interfaces
 	methods := []string{}
 	properties := []string{}
+	signals := []string{}
 	for _, item := range node.Interfaces {
 		if item.Name != iface {
 			continue
@@ -372,13 +793,20 @@ func Introspect() map[string][]string {
 		for _, property := range item.Properties {
 			properties = append(properties, property.Name)
 		}
+
+		// Get dbus signals
+		for _, signal := range item.Signals {
+			signals = append(signals, signal.Name)
+		}
 	}
 	sort.Strings(methods)
 	sort.Strings(properties)
+	sort.Strings(signals)
 
 	return map[string][]string{
 		"Methods":    methods,
 		"Properties": properties,
+		"Signals":    signals,
 	}
 }
 
@@ -392,9 +820,12 @@ func Method(name string, args []string) {
 	// Convert arguments
 	variants := make([]interface{}, len(args))
 	for i, value := range args {
-		integer, err := strconv.Atoi(value)
-		if err == nil {
+		if integer, err := strconv.Atoi(value); err == nil {
 			variants[i] = dbus.MakeVariant(integer)
+		} else if boolean, err := strconv.ParseBool(value); err == nil {
+			variants[i] = dbus.MakeVariant(boolean)
+		} else if floats, err := parseFloats(value); err == nil {
+			variants[i] = dbus.MakeVariant(floats)
 		} else {
 			variants[i] = dbus.MakeVariant(value)
 		}
@@ -408,7 +839,9 @@ func Method(name string, args []string) {
 
 	// Print reply
 	var reply string
-	call.Store(&reply)
+	if call.Store(&reply) != nil {
+		reply = dataMap("Result", name, common.Map{"Values": call.Body})
+	}
 	fmt.Println(reply)
 }
 
@@ -431,7 +864,11 @@ func Property(name string) {
 	// Print reply
 	var reply dbus.Variant
 	call.Store(&reply)
-	print("Property", name, variantToMap(reply))
+	value := variantToMap(reply)
+	if value == nil {
+		value = common.Map{"Value": reply.Value()}
+	}
+	print("Property", name, value)
 }
 
 func Listen(args []string) {
@@ -445,6 +882,7 @@ func Listen(args []string) {
 	call := conn.BusObject().Call("org.freedesktop.DBus.Monitoring.BecomeMonitor", 0, []string{
 		fmt.Sprintf("type='signal',interface='org.freedesktop.DBus.Properties',member='PropertiesChanged',path='%s'", opath),
 		fmt.Sprintf("type='method_call',interface='%s',path='%s'", iface, opath),
+		fmt.Sprintf("type='signal',interface='%s',path='%s'", iface, opath),
 	}, uint(0))
 	if call.Err != nil {
 		fatal("Error becoming dbus monitor", call.Err)
@@ -470,6 +908,9 @@ func Listen(args []string) {
 			}
 		default:
 			typ := "Method"
+			if msg.Type == dbus.TypeSignal {
+				typ = "Signal"
+			}
 			filter := fmt.Sprintf("%s:%s", typ, method)
 			if len(args) == 0 || common.IsInList(filter, args) {
 				print(typ, method, common.Map{"Body": bodyToString(msg.Body)})
@@ -484,6 +925,7 @@ func Disconnect() {
 	}{
 		Event: "exit",
 	})
+	CloseSocket()
 }
 
 func GetProperty(name string) common.Map {
@@ -495,6 +937,7 @@ func GetProperty(name string) common.Map {
 }
 
 func SetProperty(name string, obj interface{}) {
+	Publish(name, obj)
 	if props == nil {
 		return
 	}
@@ -529,6 +972,21 @@ func bodyToString(obj interface{}) string {
 	return r.Replace(fmt.Sprint(obj))
 }
 
+func parseFloats(value string) ([]float64, error) {
+	floats := []float64{}
+
+	// Parse comma separated float values
+	for _, v := range strings.Split(value, ",") {
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, err
+		}
+		floats = append(floats, f)
+	}
+
+	return floats, nil
+}
+
 func dataMap(typ string, name string, data common.Map) string {
 	time := time.Now().UnixMilli()
 	process := common.Process.Id
@@ -0,0 +1,174 @@
+package input
+
+import (
+	"sort"
+
+	"github.com/jezek/xgb/xproto"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/desktop"
+	"github.com/leukipp/cortile/v2/store"
+)
+
+const (
+	ApiVersion uint32 = 1 // Version of dbus query schema, incremented on breaking changes
+)
+
+// ClientInfo describes a tracked window, signature (isssiiiiiibbb).
+type ClientInfo struct {
+	Id       int32  // Window id
+	Class    string // Window class name
+	Name     string // Window title name
+	Layout   string // Active layout name of client workspace
+	Desktop  int32  // Desktop index
+	Screen   int32  // Screen index
+	X        int32  // Window x position (including decorations)
+	Y        int32  // Window y position (including decorations)
+	Width    int32  // Window width (including decorations)
+	Height   int32  // Window height (including decorations)
+	Master   bool   // Window is in master area
+	Floating bool   // Window is excluded from tiling
+	Urgent   bool   // Window demands attention
+}
+
+// WorkspaceInfo describes a workspace, signature (siibsai).
+type WorkspaceInfo struct {
+	Name    string  // Workspace name
+	Desktop int32   // Desktop index
+	Screen  int32   // Screen index
+	Enabled bool    // Tiling is enabled
+	Layout  string  // Active layout name
+	Clients []int32 // Window ids of tiled clients
+}
+
+// LayoutInfo describes the active layout of a workspace, signature (siibbaiaiiiadadad).
+type LayoutInfo struct {
+	Name         string    // Layout name
+	Desktop      int32     // Desktop index
+	Screen       int32     // Screen index
+	Enabled      bool      // Tiling is enabled
+	Decoration   bool      // Window decorations are enabled
+	Masters      []int32   // Window ids in master area
+	Slaves       []int32   // Window ids in slave area
+	MasterMax    int32     // Maximum number of visible masters
+	SlaveMax     int32     // Maximum number of visible slaves
+	MasterSlave  []float64 // Master-slave area proportions
+	MasterMaster []float64 // Master-master proportions of visible masters
+	SlaveSlave   []float64 // Slave-slave proportions of visible slaves
+}
+
+func clientInfos(tr *desktop.Tracker) []ClientInfo {
+	clients := []ClientInfo{}
+
+	// Obtain tracked and floated clients sorted by id
+	for _, c := range tr.Clients {
+		clients = append(clients, clientInfo(c, tr))
+	}
+	for w := range tr.FloatedWindows {
+		if info, ok := floatedInfo(w, tr); ok {
+			clients = append(clients, info)
+		}
+	}
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].Id < clients[j].Id
+	})
+
+	return clients
+}
+
+func clientInfo(c *store.Client, tr *desktop.Tracker) ClientInfo {
+	info := windowInfo(c.Window.Id, c.Latest, tr)
+
+	// Obtain client workspace infos
+	if ws := tr.ClientWorkspace(c); ws != nil {
+		info.Layout = ws.ActiveLayout().GetName()
+		info.Master = ws.ActiveLayout().GetManager().IsMaster(c)
+	}
+
+	return info
+}
+
+func floatedInfo(w xproto.Window, tr *desktop.Tracker) (ClientInfo, bool) {
+	if !tr.FloatedWindows[w] || tr.Clients[w] != nil {
+		return ClientInfo{}, false
+	}
+
+	// Skip closed windows
+	stacked := false
+	for _, s := range store.Windows.Stacked {
+		stacked = stacked || s.Id == w
+	}
+	if !stacked {
+		return ClientInfo{}, false
+	}
+
+	// Obtain untracked window infos
+	return windowInfo(w, store.GetInfo(w), tr), true
+}
+
+func windowInfo(w xproto.Window, latest *store.Info, tr *desktop.Tracker) ClientInfo {
+	info := ClientInfo{
+		Id:       int32(w),
+		Class:    latest.Class,
+		Name:     latest.Name,
+		Desktop:  int32(latest.Location.Desktop),
+		Screen:   int32(latest.Location.Screen),
+		X:        int32(latest.Dimensions.Geometry.X),
+		Y:        int32(latest.Dimensions.Geometry.Y),
+		Width:    int32(latest.Dimensions.Geometry.Width),
+		Height:   int32(latest.Dimensions.Geometry.Height),
+		Floating: tr.FloatedWindows[w],
+	}
+	_, info.Urgent = tr.UrgentWindows[w]
+
+	return info
+}
+
+func workspaceInfo(ws *desktop.Workspace) WorkspaceInfo {
+	info := WorkspaceInfo{
+		Name:    ws.Name,
+		Desktop: int32(ws.Location.Desktop),
+		Screen:  int32(ws.Location.Screen),
+		Enabled: ws.TilingEnabled(),
+		Layout:  ws.ActiveLayout().GetName(),
+		Clients: []int32{},
+	}
+
+	// Obtain tiled client ids
+	for _, c := range ws.ActiveLayout().GetManager().Clients(store.Stacked) {
+		info.Clients = append(info.Clients, int32(c.Window.Id))
+	}
+
+	return info
+}
+
+func layoutInfo(ws *desktop.Workspace) LayoutInfo {
+	mg := ws.ActiveLayout().GetManager()
+	msize := common.MinInt(len(mg.Masters.Stacked), mg.Masters.Maximum)
+	ssize := common.MinInt(len(mg.Slaves.Stacked), mg.Slaves.Maximum)
+
+	info := LayoutInfo{
+		Name:         ws.ActiveLayout().GetName(),
+		Desktop:      int32(ws.Location.Desktop),
+		Screen:       int32(ws.Location.Screen),
+		Enabled:      ws.TilingEnabled(),
+		Decoration:   mg.DecorationEnabled(),
+		Masters:      []int32{},
+		Slaves:       []int32{},
+		MasterMax:    int32(mg.Masters.Maximum),
+		SlaveMax:     int32(mg.Slaves.Maximum),
+		MasterSlave:  append([]float64{}, mg.Proportions.MasterSlave[2]...),
+		MasterMaster: append([]float64{}, mg.Proportions.MasterMaster[msize]...),
+		SlaveSlave:   append([]float64{}, mg.Proportions.SlaveSlave[ssize]...),
+	}
+
+	// Obtain master and slave ids
+	for _, c := range mg.Masters.Stacked {
+		info.Masters = append(info.Masters, int32(c.Window.Id))
+	}
+	for _, c := range mg.Slaves.Stacked {
+		info.Slaves = append(info.Slaves, int32(c.Window.Id))
+	}
+
+	return info
+}
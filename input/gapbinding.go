@@ -0,0 +1,184 @@
+package input
+
+import (
+	"github.com/jezek/xgb/xproto"
+
+	"github.com/jezek/xgbutil"
+	"github.com/jezek/xgbutil/ewmh"
+	"github.com/jezek/xgbutil/mousebind"
+	"github.com/jezek/xgbutil/xcursor"
+	"github.com/jezek/xgbutil/xwindow"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/desktop"
+	"github.com/leukipp/cortile/v2/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	gap     *Gap                     // Gap handle under pointer
+	gapWin  *xwindow.Window          // Input window covering the gap handle
+	cursors map[uint16]xproto.Cursor // Cursors shown over gap handles
+)
+
+type Gap struct {
+	Client   *store.Client    // Client resized by dragging the gap
+	Edges    store.Directions // Client edge moved by dragging the gap
+	Geometry common.Geometry  // Dimensions of the gap handle
+	Shape    uint16           // Cursor shape of the gap handle
+}
+
+func bindGaps(tr *desktop.Tracker) {
+	win, err := xwindow.Generate(store.X)
+	if err != nil {
+		log.Warn("Error generating gap window: ", err)
+		return
+	}
+
+	// Create unmanaged input only window
+	mask := uint32(xproto.EventMaskButtonPress | xproto.EventMaskButtonRelease | xproto.EventMaskPointerMotion)
+	err = xproto.CreateWindowChecked(store.X.Conn(), 0, win.Id, store.X.RootWin(), 0, 0, 1, 1, 0,
+		xproto.WindowClassInputOnly, 0, xproto.CwOverrideRedirect|xproto.CwEventMask, []uint32{1, mask}).Check()
+	if err != nil {
+		log.Warn("Error creating gap window: ", err)
+		return
+	}
+	gapWin = win
+
+	// Resize client by dragging the gap handle
+	mousebind.Drag(store.X, win.Id, win.Id, "1", false,
+		func(X *xgbutil.XUtil, rx, ry, ex, ey int) (bool, xproto.Cursor) {
+			if gap == nil {
+				return false, 0
+			}
+			return startDrag(gap.Client, "resize", *common.CreatePoint(rx, ry), gap.Edges, gap.Shape)
+		},
+		func(X *xgbutil.XUtil, rx, ry, ex, ey int) {
+			stepDrag(*common.CreatePoint(rx, ry))
+		},
+		func(X *xgbutil.XUtil, rx, ry, ex, ey int) {
+			endDrag()
+		})
+}
+
+func updateGap(tr *desktop.Tracker) {
+	if gapWin == nil || drag != nil {
+		return
+	}
+
+	// Hide gap handle if pointer left the gap
+	g := gapAt(tr, store.Pointer.Position)
+	if g == nil {
+		if gap != nil {
+			gapWin.Unmap()
+		}
+		gap = nil
+		return
+	}
+	if gap != nil && gap.Geometry == g.Geometry {
+		return
+	}
+	gap = g
+
+	// Show gap handle with resize cursor
+	x, y, w, h := g.Geometry.Pieces()
+	gapWin.MoveResize(x, y, w, h)
+	gapWin.Change(xproto.CwCursor, uint32(gapCursor(g.Shape)))
+	gapWin.Map()
+	gapWin.Stack(xproto.StackModeAbove)
+}
+
+func gapAt(tr *desktop.Tracker, p common.Point) *Gap {
+	size := common.Config.WindowGapSize
+	if size <= 0 {
+		return nil
+	}
+
+	// Ignore pointer above windows
+	ws := tr.WorkspaceAt(store.Workplace.CurrentDesktop, store.ScreenGet(p))
+	if ws == nil || ws.TilingDisabled() || tr.ClientAt(ws, p) != nil || !isDesktopAt() {
+		return nil
+	}
+
+	// Obtain clients left and right of the gap
+	l, r := neighborAt(tr, ws, p, -1, 0, size), neighborAt(tr, ws, p, 1, 0, size)
+	if l != nil && r != nil && l != r {
+		lx, ly, lw, lh := l.OuterGeometry()
+		rx, ry, _, rh := r.OuterGeometry()
+		y0, y1 := common.MaxInt(ly, ry), common.MinInt(ly+lh, ry+rh)
+		if rx > lx+lw && y1 > y0 {
+			return &Gap{
+				Client:   l,
+				Edges:    store.Directions{Right: true},
+				Geometry: common.Geometry{X: lx + lw, Y: y0, Width: rx - lx - lw, Height: y1 - y0},
+				Shape:    xcursor.SBHDoubleArrow,
+			}
+		}
+	}
+
+	// Obtain clients above and below the gap
+	t, b := neighborAt(tr, ws, p, 0, -1, size), neighborAt(tr, ws, p, 0, 1, size)
+	if t != nil && b != nil && t != b {
+		tx, ty, tw, th := t.OuterGeometry()
+		bx, by, bw, _ := b.OuterGeometry()
+		x0, x1 := common.MaxInt(tx, bx), common.MinInt(tx+tw, bx+bw)
+		if by > ty+th && x1 > x0 {
+			return &Gap{
+				Client:   t,
+				Edges:    store.Directions{Bottom: true},
+				Geometry: common.Geometry{X: x0, Y: ty + th, Width: x1 - x0, Height: by - ty - th},
+				Shape:    xcursor.SBVDoubleArrow,
+			}
+		}
+	}
+
+	return nil
+}
+
+func neighborAt(tr *desktop.Tracker, ws *desktop.Workspace, p common.Point, dx int, dy int, size int) *store.Client {
+
+	// Search client within gap distance
+	for d := 1; d <= size+1; d++ {
+		if c := tr.ClientAt(ws, *common.CreatePoint(p.X+dx*d, p.Y+dy*d)); c != nil {
+			return c
+		}
+	}
+
+	return nil
+}
+
+func isDesktopAt() bool {
+	reply, err := xproto.QueryPointer(store.X.Conn(), store.X.RootWin()).Reply()
+	if err != nil {
+		return false
+	}
+
+	// Check if pointer hovers the root, a desktop or the gap window
+	if reply.Child == 0 || reply.Child == gapWin.Id {
+		return true
+	}
+	types, err := ewmh.WmWindowTypeGet(store.X, reply.Child)
+	if err != nil {
+		return false
+	}
+
+	return common.IsInList("_NET_WM_WINDOW_TYPE_DESKTOP", types)
+}
+
+func gapCursor(shape uint16) xproto.Cursor {
+	if cursors == nil {
+		cursors = make(map[uint16]xproto.Cursor)
+	}
+
+	// Create cursor once per shape
+	if _, ok := cursors[shape]; !ok {
+		cursor, err := xcursor.CreateCursor(store.X, shape)
+		if err != nil {
+			log.Warn("Error creating cursor: ", err)
+		}
+		cursors[shape] = cursor
+	}
+
+	return cursors[shape]
+}
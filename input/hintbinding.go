@@ -0,0 +1,159 @@
+package input
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jezek/xgb/xproto"
+
+	"github.com/jezek/xgbutil"
+	"github.com/jezek/xgbutil/keybind"
+	"github.com/jezek/xgbutil/xevent"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/desktop"
+	"github.com/leukipp/cortile/v2/store"
+	"github.com/leukipp/cortile/v2/ui"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	hint *Hint // Active hint selection
+)
+
+type Hint struct {
+	Window xproto.Window            // Hint window receiving key events
+	Labels map[string]*store.Client // Clients mapped by hint label
+	Input  string                   // Typed hint label characters
+	Select func(c *store.Client)    // Callback for selected client
+}
+
+func showHints(tr *desktop.Tracker, fun func(c *store.Client)) bool {
+	closeHints()
+
+	// Obtain visible tiled clients on current desktop
+	clients := []*store.Client{}
+	for _, ws := range tr.Workspaces {
+		if ws.Location.Desktop != store.Workplace.CurrentDesktop || ws.TilingDisabled() {
+			continue
+		}
+		clients = append(clients, ws.VisibleClients()...)
+	}
+	if len(clients) == 0 {
+		return false
+	}
+
+	// Sort clients by position
+	sort.Slice(clients, func(i, j int) bool {
+		xi, yi, _, _ := clients[i].OuterGeometry()
+		xj, yj, _, _ := clients[j].OuterGeometry()
+		if xi == xj {
+			return yi < yj
+		}
+		return xi < xj
+	})
+
+	// Assign hint labels to clients
+	labels := map[string]*store.Client{}
+	for i, label := range hintLabels(len(clients)) {
+		labels[label] = clients[i]
+	}
+
+	// Show hint labels
+	win := ui.ShowHints(labels)
+	if win == nil {
+		return false
+	}
+
+	// Grab keyboard and redirect events to hint window
+	err := keybind.SmartGrab(store.X, win.Id)
+	if err != nil {
+		log.Warn("Error grabbing keyboard: ", err)
+		ui.CloseHints()
+		return false
+	}
+	xevent.KeyPressFun(func(X *xgbutil.XUtil, ev xevent.KeyPressEvent) {
+		typeHint(keybind.LookupString(X, ev.State, ev.Detail))
+	}).Connect(store.X, win.Id)
+
+	hint = &Hint{
+		Window: win.Id,
+		Labels: labels,
+		Select: fun,
+	}
+
+	return true
+}
+
+func typeHint(key string) {
+	if hint == nil {
+		return
+	}
+
+	// Cancel selection on escape
+	if key == "Escape" {
+		closeHints()
+		return
+	}
+
+	// Ignore modifier keys
+	if len(key) != 1 {
+		return
+	}
+	hint.Input += strings.ToLower(key)
+
+	// Select client with matching label
+	if c, ok := hint.Labels[hint.Input]; ok {
+		fun := hint.Select
+		closeHints()
+		fun(c)
+		return
+	}
+
+	// Cancel selection on invalid label
+	for label := range hint.Labels {
+		if strings.HasPrefix(label, hint.Input) {
+			return
+		}
+	}
+	closeHints()
+}
+
+func closeHints() {
+	if hint == nil {
+		return
+	}
+	window := hint.Window
+	hint = nil
+
+	// Release keyboard, detach key handler and close hint labels
+	keybind.SmartUngrab(store.X)
+	xevent.Detach(store.X, window)
+	ui.CloseHints()
+}
+
+func hintLabels(count int) []string {
+	chars := []rune(strings.ToLower(common.Config.WindowHintsChars))
+	if len(chars) < 2 {
+		chars = []rune("asdfghjkl")
+	}
+
+	// Obtain minimal label length
+	length, capacity := 1, len(chars)
+	for capacity < count {
+		length, capacity = length+1, capacity*len(chars)
+	}
+
+	// Generate labels of equal length
+	labels := make([]string, count)
+	for i := range labels {
+		label := make([]rune, length)
+		for j, n := length-1, i; j >= 0; j, n = j-1, n/len(chars) {
+			label[j] = chars[n%len(chars)]
+		}
+		labels[i] = string(label)
+	}
+
+	return labels
+}
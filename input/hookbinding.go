@@ -0,0 +1,192 @@
+package input
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/jezek/xgb/xproto"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/desktop"
+	"github.com/leukipp/cortile/v2/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	hook   *Hook      // Stores previous hook state (for comparison only)
+	hookMu sync.Mutex // Lock for hook state updates
+)
+
+var (
+	hookEvents = []string{"startup", "exit", "layout_change", "tiling_enable", "tiling_disable", "client_add", "client_remove", "focus_change", "screen_change", "desktop_change"} // Supported hook event names
+)
+
+type Hook struct {
+	Location store.Location                  // Current desktop and screen
+	Clients  map[xproto.Window]*store.Client // Tracked client windows
+	Layouts  map[store.Location]string       // Active layout names
+	Tilings  map[store.Location]bool         // Tiling enabled states
+	Focused  xproto.Window                   // Active window
+}
+
+func BindHooks(tr *desktop.Tracker) {
+	for name := range common.Config.Hooks {
+		if !common.IsInList(name, hookEvents) {
+			log.Warn("Unknown hook event \"", name, "\"")
+		}
+	}
+
+	// Attach execute events
+	OnExecute(func(action string, desktop uint, screen uint) {
+		if common.IsInList(action, []string{"exit", "restart"}) {
+			executeHook("exit", tr.WorkspaceAt(desktop, screen), 0, nil, action)
+			return
+		}
+		updateHooks(tr, action)
+	})
+
+	// Attach state events
+	store.OnStateUpdate(func(state string, desktop uint, screen uint) {
+		updateHooks(tr, "")
+	})
+
+	// Execute startup hook
+	executeHook("startup", tr.ActiveWorkspace(), 0, nil, "")
+}
+
+func updateHooks(tr *desktop.Tracker, action string) {
+	hookMu.Lock()
+	defer hookMu.Unlock()
+
+	// Store initial hook state
+	previous := hook
+	hook = createHook(tr)
+	if previous == nil {
+		return
+	}
+	ws := tr.ActiveWorkspace()
+
+	// Execute desktop and screen hooks
+	if hook.Location.Desktop != previous.Location.Desktop {
+		executeHook("desktop_change", ws, 0, nil, action)
+	}
+	if hook.Location.Screen != previous.Location.Screen {
+		executeHook("screen_change", ws, 0, nil, action)
+	}
+
+	// Execute client hooks
+	for w, c := range hook.Clients {
+		if _, ok := previous.Clients[w]; !ok {
+			executeHook("client_add", tr.ClientWorkspace(c), w, c, action)
+		}
+	}
+	for w, c := range previous.Clients {
+		if _, ok := hook.Clients[w]; !ok {
+			executeHook("client_remove", tr.Workspaces[c.Latest.Location], w, c, action)
+		}
+	}
+
+	// Execute focus hook
+	if hook.Focused != previous.Focused && hook.Focused != 0 {
+		executeHook("focus_change", ws, hook.Focused, hook.Clients[hook.Focused], action)
+	}
+
+	// Execute layout and tiling hooks
+	for location, name := range hook.Layouts {
+		if layout, ok := previous.Layouts[location]; ok && layout != name {
+			executeHook("layout_change", tr.Workspaces[location], 0, nil, action)
+		}
+	}
+	for location, enabled := range hook.Tilings {
+		if tiling, ok := previous.Tilings[location]; ok && tiling != enabled {
+			if enabled {
+				executeHook("tiling_enable", tr.Workspaces[location], 0, nil, action)
+			} else {
+				executeHook("tiling_disable", tr.Workspaces[location], 0, nil, action)
+			}
+		}
+	}
+}
+
+func createHook(tr *desktop.Tracker) *Hook {
+	h := &Hook{
+		Location: store.Location{Desktop: store.Workplace.CurrentDesktop, Screen: store.Workplace.CurrentScreen},
+		Clients:  make(map[xproto.Window]*store.Client),
+		Layouts:  make(map[store.Location]string),
+		Tilings:  make(map[store.Location]bool),
+		Focused:  store.Windows.Active.Id,
+	}
+
+	// Copy client and workspace states
+	for w, c := range tr.Clients {
+		h.Clients[w] = c
+	}
+	for location, ws := range tr.Workspaces {
+		h.Layouts[location] = ws.ActiveLayout().GetName()
+		h.Tilings[location] = ws.TilingEnabled()
+	}
+
+	return h
+}
+
+func executeHook(name string, ws *desktop.Workspace, w xproto.Window, c *store.Client, action string) {
+	executeScriptHandlers(name, ws, w, c, action)
+
+	command := common.Config.Hooks[name]
+	if len(command) == 0 {
+		return
+	}
+	log.Info("Execute hook ", name, " [", command, "]")
+
+	// Obtain hook context
+	env := append([]string{
+		fmt.Sprintf("CORTILE_EVENT=%s", name),
+		fmt.Sprintf("CORTILE_ACTION=%s", action),
+	}, contextEnv(ws, w, c)...)
+
+	// Execute hook command asynchronously
+	cmd := exec.Command("/bin/sh", "-c", command)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Start(); err != nil {
+		log.Warn("Error executing hook ", name, ": ", err)
+		return
+	}
+
+	// Release process resources
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			log.Warn("Error on hook ", name, ": ", err)
+		}
+	}()
+}
+
+func contextEnv(ws *desktop.Workspace, w xproto.Window, c *store.Client) []string {
+	env := []string{}
+
+	// Obtain workspace and window context
+	if ws != nil {
+		env = append(env,
+			fmt.Sprintf("CORTILE_DESKTOP=%d", ws.Location.Desktop),
+			fmt.Sprintf("CORTILE_SCREEN=%d", ws.Location.Screen),
+			fmt.Sprintf("CORTILE_WORKSPACE=%s", ws.Name),
+			fmt.Sprintf("CORTILE_LAYOUT=%s", ws.ActiveLayout().GetName()),
+			fmt.Sprintf("CORTILE_TILING=%t", ws.TilingEnabled()),
+		)
+	}
+	if w != 0 {
+		env = append(env, fmt.Sprintf("CORTILE_WINDOW_ID=%d", w))
+	}
+	if c != nil && c.Latest != nil {
+		env = append(env,
+			fmt.Sprintf("CORTILE_WINDOW_CLASS=%s", c.Latest.Class),
+			fmt.Sprintf("CORTILE_WINDOW_NAME=%s", c.Latest.Name),
+		)
+	}
+
+	return env
+}
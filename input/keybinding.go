@@ -1,19 +1,38 @@
 package input
 
 import (
+	"sort"
 	"strings"
+	"time"
+
+	"github.com/jezek/xgb/xproto"
 
 	"github.com/jezek/xgbutil"
 	"github.com/jezek/xgbutil/keybind"
 	"github.com/jezek/xgbutil/xevent"
+	"github.com/jezek/xgbutil/xwindow"
 
 	"github.com/leukipp/cortile/v2/common"
 	"github.com/leukipp/cortile/v2/desktop"
 	"github.com/leukipp/cortile/v2/store"
+	"github.com/leukipp/cortile/v2/ui"
 
 	log "github.com/sirupsen/logrus"
 )
 
+var (
+	mode     string    // Active keybinding mode
+	sequence *Sequence // Active key sequence
+)
+
+type Sequence struct {
+	Step    int               // Number of typed keys after leader
+	Mod     string            // Modifier used with leader key
+	Actions map[string]string // Actions mapped by keys after leader
+	Timer   *time.Timer       // Timer to show hints and cancel sequence
+	Hints   *xwindow.Window   // Overlay window with continuations
+}
+
 func BindKeys(tr *desktop.Tracker) {
 	keybind.Initialize(store.X)
 
@@ -33,13 +52,50 @@ func BindKeys(tr *desktop.Tracker) {
 	}
 
 	// Bind keyboard shortcuts
+	sequences := map[string]map[string]map[string]string{}
 	for a, ak := range actions {
 		for m, mk := range mods {
-			if len(mk) == 0 {
-				bind(ak, a, m, tr)
-			} else {
-				bind(mk+"-"+ak, a, m, tr)
+			key := ak
+			if len(mk) > 0 {
+				key = mk + "-" + ak
+			}
+
+			// Group key sequences by leader
+			if keys := strings.Fields(key); len(keys) > 1 {
+				if sequences[m] == nil {
+					sequences[m] = map[string]map[string]string{}
+				}
+				if sequences[m][keys[0]] == nil {
+					sequences[m][keys[0]] = map[string]string{}
+				}
+				sequences[m][keys[0]][strings.Join(keys[1:], " ")] = a
+				continue
 			}
+
+			bind(key, a, m, tr)
+		}
+	}
+
+	// Bind key sequences
+	for m, leaders := range sequences {
+		for leader, keys := range leaders {
+			bindSequence(leader, keys, m, tr)
+		}
+	}
+	xevent.KeyPressFun(func(X *xgbutil.XUtil, ev xevent.KeyPressEvent) {
+		typeSequence(tr, ev)
+	}).Connect(store.X, store.X.Dummy())
+
+	// Bind keyboard modes
+	for m, keys := range common.Config.Modes {
+		if _, ok := keys["mode_exit"]; !ok {
+			bindMode("Escape", "mode_exit", m, tr)
+		}
+		for a, ak := range keys {
+			if len(ak) == 0 {
+				continue
+			}
+			bindMode(ak, a, m, tr)
 		}
 	}
 
@@ -47,9 +103,49 @@ func BindKeys(tr *desktop.Tracker) {
 	go action(tr.Channels.Action, tr)
 }
 
+func EnableMode(tr *desktop.Tracker, name string) bool {
+	keys, ok := common.Config.Modes[name]
+	if !ok {
+		log.Warn("Error on mode ", name, ": mode not defined")
+		return false
+	}
+	DisableMode(tr)
+
+	// Grab mode keys
+	for _, key := range modeKeys(keys) {
+		grabKey(key, true)
+	}
+	mode = name
+	log.Info("Enable keybinding mode [", mode, "]")
+
+	ui.UpdateMode(tr.ActiveWorkspace(), mode)
+
+	return true
+}
+
+func DisableMode(tr *desktop.Tracker) bool {
+	if len(mode) == 0 {
+		return false
+	}
+
+	// Release mode keys
+	for _, key := range modeKeys(common.Config.Modes[mode]) {
+		grabKey(key, false)
+	}
+	log.Info("Disable keybinding mode [", mode, "]")
+	mode = ""
+
+	ui.UpdateMode(tr.ActiveWorkspace(), mode)
+
+	return true
+}
+
 func bind(key string, action string, mod string, tr *desktop.Tracker) {
 	err := keybind.KeyPressFun(func(X *xgbutil.XUtil, ev xevent.KeyPressEvent) {
-		ExecuteActions(action, tr, mod)
+		active, geom := activeGeometry(tr)
+		if ExecuteActions(action, tr, mod) {
+			warpPointer(tr, active, geom)
+		}
 	}).Connect(store.X, store.X.RootWin(), key, true)
 
 	if err != nil {
@@ -57,6 +153,199 @@ func bind(key string, action string, mod string, tr *desktop.Tracker) {
 	}
 }
 
+func bindSequence(leader string, actions map[string]string, mod string, tr *desktop.Tracker) {
+	err := keybind.KeyPressFun(func(X *xgbutil.XUtil, ev xevent.KeyPressEvent) {
+		closeSequence()
+
+		// Grab keyboard for following keys
+		err := keybind.DummyGrab(store.X)
+		if err != nil {
+			log.Warn("Error grabbing keyboard: ", err)
+			return
+		}
+
+		sequence = &Sequence{
+			Mod:     mod,
+			Actions: actions,
+		}
+		waitSequence(tr, sequence)
+	}).Connect(store.X, store.X.RootWin(), leader, true)
+
+	if err != nil {
+		log.Warn("Error on sequence ", leader, ": ", err)
+	}
+}
+
+func typeSequence(tr *desktop.Tracker, ev xevent.KeyPressEvent) {
+	if sequence == nil {
+		return
+	}
+
+	// Ignore modifier keys
+	if keybind.ModGet(store.X, ev.Detail) != 0 {
+		return
+	}
+
+	// Obtain sequences matching the typed key
+	matches := map[string]string{}
+	for keys, action := range sequence.Actions {
+		fields := strings.Fields(keys)
+		if len(fields) > sequence.Step && matchKey(fields[sequence.Step], ev.State, ev.Detail) {
+			matches[keys] = action
+		}
+	}
+
+	// Cancel on invalid sequence
+	if len(matches) == 0 {
+		log.Info("Cancel key sequence")
+		closeSequence()
+		return
+	}
+	sequence.Step += 1
+	sequence.Actions = matches
+
+	// Execute action of completed sequence
+	for keys, action := range matches {
+		if len(strings.Fields(keys)) != sequence.Step {
+			continue
+		}
+		mod := sequence.Mod
+		closeSequence()
+		active, geom := activeGeometry(tr)
+		if ExecuteActions(action, tr, mod) {
+			warpPointer(tr, active, geom)
+		}
+		return
+	}
+
+	waitSequence(tr, sequence)
+}
+
+func waitSequence(tr *desktop.Tracker, s *Sequence) {
+	timeout := time.Duration(common.Config.KeysSequenceTimeout) * time.Millisecond
+	if timeout <= 0 {
+		timeout = 1000 * time.Millisecond
+	}
+	if s.Timer != nil {
+		s.Timer.Stop()
+	}
+
+	// Show possible continuations on timeout
+	step := s.Step
+	s.Timer = time.AfterFunc(timeout, func() {
+		store.Enqueue(func() {
+			if sequence != s || s.Step != step {
+				return
+			}
+			hints := []string{}
+			for keys, action := range s.Actions {
+				hints = append(hints, strings.Join(strings.Fields(keys)[s.Step:], " ")+"  "+action)
+			}
+			sort.Strings(hints)
+			s.Hints = ui.ShowKeys(tr.ActiveWorkspace(), hints)
+
+			// Cancel sequence on second timeout
+			s.Timer = time.AfterFunc(2*timeout, func() {
+				store.Enqueue(func() {
+					if sequence == s && s.Step == step {
+						closeSequence()
+					}
+				})
+			})
+		})
+	})
+}
+
+func closeSequence() {
+	if sequence == nil {
+		return
+	}
+
+	// Release keyboard and close hints
+	if sequence.Timer != nil {
+		sequence.Timer.Stop()
+	}
+	if sequence.Hints != nil {
+		sequence.Hints.Destroy()
+	}
+	keybind.DummyUngrab(store.X)
+
+	sequence = nil
+}
+
+func matchKey(key string, state uint16, code xproto.Keycode) bool {
+	mods, codes, err := keybind.ParseString(store.X, key)
+	if err != nil {
+		return false
+	}
+
+	// Compare modifiers without lock keys
+	state &= xproto.ModMaskShift | xproto.ModMaskControl | xproto.ModMask1 | xproto.ModMask3 | xproto.ModMask4 | xproto.ModMask5
+	if state != mods {
+		return false
+	}
+
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+func bindMode(key string, action string, name string, tr *desktop.Tracker) {
+	err := keybind.KeyPressFun(func(X *xgbutil.XUtil, ev xevent.KeyPressEvent) {
+		if mode != name {
+			return
+		}
+		active, geom := activeGeometry(tr)
+		if ExecuteActions(action, tr, "current") {
+			warpPointer(tr, active, geom)
+		}
+	}).Connect(store.X, store.X.RootWin(), key, false)
+
+	if err != nil {
+		log.Warn("Error on mode ", name, " action ", action, ": ", err)
+	}
+}
+
+func grabKey(key string, grab bool) {
+	mods, codes, err := keybind.ParseString(store.X, key)
+	if err != nil {
+		log.Warn("Error parsing key ", key, ": ", err)
+		return
+	}
+
+	// Grab or release key codes
+	for _, code := range codes {
+		if !grab {
+			keybind.Ungrab(store.X, store.X.RootWin(), mods, code)
+			continue
+		}
+		err := keybind.GrabChecked(store.X, store.X.RootWin(), mods, code)
+		if err != nil {
+			log.Warn("Error grabbing key ", key, ": ", err)
+		}
+	}
+}
+
+func modeKeys(keys map[string]string) []string {
+	result := []string{}
+
+	// Obtain mode keys including default exit
+	if _, ok := keys["mode_exit"]; !ok {
+		result = append(result, "Escape")
+	}
+	for _, key := range keys {
+		if len(key) > 0 {
+			result = append(result, key)
+		}
+	}
+
+	return result
+}
+
 func action(ch chan string, tr *desktop.Tracker) {
 	for {
 		ExecuteAction(<-ch, tr, tr.ActiveWorkspace())
@@ -1,8 +1,16 @@
 package input
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/jezek/xgb/xproto"
+
+	"github.com/jezek/xgbutil"
+	"github.com/jezek/xgbutil/mousebind"
+	"github.com/jezek/xgbutil/xcursor"
+	"github.com/jezek/xgbutil/xevent"
+
 	"github.com/leukipp/cortile/v2/common"
 	"github.com/leukipp/cortile/v2/desktop"
 	"github.com/leukipp/cortile/v2/store"
@@ -15,9 +23,28 @@ var (
 	workspace *desktop.Workspace // Stores previous workspace (for comparison only)
 	pointer   *store.XPointer    // Stores previous pointer (for comparison only)
 	hover     *time.Timer        // Timer to delay hover events
+	warp      *common.Point      // Stores pointer warp position (for suppression only)
+	drag      *Drag              // Stores active modifier drag
 )
 
+type Drag struct {
+	Client   *store.Client    // Dragged client window
+	Action   string           // Drag action (move/resize)
+	Start    common.Point     // Pointer position on drag begin
+	Geometry common.Geometry  // Client geometry on drag begin
+	Edges    store.Directions // Edges changed while resizing
+	Cursor   xproto.Cursor    // Pointer cursor while dragging
+}
+
 func BindMouse(tr *desktop.Tracker) {
+	mousebind.Initialize(store.X)
+
+	// Bind modifier buttons
+	bindButtons(tr)
+
+	// Bind gap handles
+	bindGaps(tr)
+
 	poll(100, func() {
 		store.PointerUpdate(store.X)
 
@@ -33,11 +60,166 @@ func BindMouse(tr *desktop.Tracker) {
 		// Evaluate focus state
 		updateFocus(tr)
 
+		// Evaluate preview state
+		updatePreview(tr)
+
+		// Evaluate gap state
+		updateGap(tr)
+
 		// Store last pointer
 		pointer = store.Pointer
 	})
 }
 
+func bindButtons(tr *desktop.Tracker) {
+	mod := common.Config.Mouse["modifier"]
+	if len(mod) == 0 {
+		return
+	}
+	root := store.X.RootWin()
+
+	// Bind modifier drag buttons
+	for i, button := range []string{"drag_left", "drag_middle", "drag_right"} {
+		action := common.Config.Mouse[button]
+		if len(action) == 0 {
+			continue
+		}
+		buttons := fmt.Sprintf("%s-%d", mod, i+1)
+
+		// Execute click actions
+		if action != "move" && action != "resize" {
+			bindButton(buttons, action, tr)
+			continue
+		}
+
+		// Execute drag actions
+		mousebind.Drag(store.X, root, root, buttons, true,
+			func(X *xgbutil.XUtil, rx, ry, ex, ey int) (bool, xproto.Cursor) {
+				return beginDrag(tr, action, *common.CreatePoint(rx, ry))
+			},
+			func(X *xgbutil.XUtil, rx, ry, ex, ey int) {
+				stepDrag(*common.CreatePoint(rx, ry))
+			},
+			func(X *xgbutil.XUtil, rx, ry, ex, ey int) {
+				endDrag()
+			})
+	}
+
+	// Bind modifier scroll buttons
+	for i, button := range []string{"scroll_up", "scroll_down", "scroll_left", "scroll_right"} {
+		action := common.Config.Mouse[button]
+		if len(action) == 0 {
+			continue
+		}
+		bindButton(fmt.Sprintf("%s-%d", mod, i+4), action, tr)
+	}
+}
+
+func bindButton(buttons string, action string, tr *desktop.Tracker) {
+	err := mousebind.ButtonPressFun(func(X *xgbutil.XUtil, ev xevent.ButtonPressEvent) {
+		ExecuteAction(action, tr, tr.ActiveWorkspace())
+	}).Connect(store.X, store.X.RootWin(), buttons, false, true)
+
+	if err != nil {
+		log.Warn("Error on grabbing ", buttons, ": ", err)
+	}
+}
+
+func beginDrag(tr *desktop.Tracker, action string, p common.Point) (bool, xproto.Cursor) {
+	ws := tr.WorkspaceAt(store.Workplace.CurrentDesktop, store.ScreenGet(p))
+	if ws == nil {
+		return false, 0
+	}
+
+	// Obtain client under pointer
+	c := tr.ClientAt(ws, p)
+	if c == nil || store.IsMaximized(store.GetInfo(c.Window.Id)) {
+		return false, 0
+	}
+
+	// Obtain resize edges nearest to pointer
+	center := clientGeometry(c).Center()
+	edges := store.Directions{
+		Top:    p.Y < center.Y,
+		Right:  p.X >= center.X,
+		Bottom: p.Y >= center.Y,
+		Left:   p.X < center.X,
+	}
+
+	// Obtain drag cursor
+	shape := uint16(xcursor.Fleur)
+	if action == "resize" {
+		shape = xcursor.Sizing
+	}
+
+	return startDrag(c, action, p, edges, shape)
+}
+
+func startDrag(c *store.Client, action string, p common.Point, edges store.Directions, shape uint16) (bool, xproto.Cursor) {
+	log.Info("Drag client window [", action, "-", c.Latest.Class, "]")
+
+	// Store initial drag state
+	x, y, w, h := c.OuterGeometry()
+	drag = &Drag{
+		Client:   c,
+		Action:   action,
+		Start:    p,
+		Geometry: common.Geometry{X: x, Y: y, Width: w, Height: h},
+		Edges:    edges,
+	}
+
+	// Create drag cursor
+	cursor, err := xcursor.CreateCursor(store.X, shape)
+	if err != nil {
+		log.Warn("Error creating cursor: ", err)
+	}
+	drag.Cursor = cursor
+
+	return true, cursor
+}
+
+func stepDrag(p common.Point) {
+	if drag == nil {
+		return
+	}
+	dx, dy := p.X-drag.Start.X, p.Y-drag.Start.Y
+	x, y, w, h := drag.Geometry.Pieces()
+
+	// Move client window
+	if drag.Action == "move" {
+		drag.Client.DragWindow(x+dx, y+dy, 0, 0)
+		return
+	}
+
+	// Resize client window
+	if drag.Edges.Left {
+		x, w = x+dx, w-dx
+	} else if drag.Edges.Right {
+		w = w + dx
+	}
+	if drag.Edges.Top {
+		y, h = y+dy, h-dy
+	} else if drag.Edges.Bottom {
+		h = h + dy
+	}
+	if w > 0 && h > 0 {
+		drag.Client.DragWindow(x, y, w, h)
+	}
+}
+
+func endDrag() {
+	if drag == nil {
+		return
+	}
+	log.Info("Drop client window [", drag.Action, "-", drag.Client.Latest.Class, "]")
+
+	// Reset drag state
+	if drag.Cursor != 0 {
+		xproto.FreeCursor(store.X.Conn(), drag.Cursor)
+	}
+	drag = nil
+}
+
 func resetTracker(tr *desktop.Tracker) {
 	if pointer == nil || pointer.Position != store.Pointer.Position {
 		return
@@ -59,6 +241,9 @@ func updateWorkspace(tr *desktop.Tracker) {
 	// Communicate workplace change
 	tr.Channels.Event <- "workplace_change"
 
+	// Evaluate hook state
+	updateHooks(tr, "")
+
 	// Update systray icon
 	ui.UpdateIcon(ws)
 
@@ -67,16 +252,91 @@ func updateWorkspace(tr *desktop.Tracker) {
 }
 
 func updateCorner(tr *desktop.Tracker) {
-	hc := store.HotCorner()
-	if hc == nil {
+
+	// Execute enter action
+	if hc := store.HotCorner(); hc != nil {
+		tr.Channels.Event <- "corner_change"
+		ExecuteAction(cornerAction(hc, hc.Name), tr, tr.ActiveWorkspace())
+	}
+
+	// Ignore stationary pointer buttons
+	if pointer == nil || pointer.Button.Left || !store.Pointer.Button.Left {
 		return
 	}
 
-	// Communicate corner change
-	tr.Channels.Event <- "corner_change"
+	// Execute click action
+	if hc := store.ActiveCorner(); hc != nil {
+		tr.Channels.Event <- "corner_change"
+		ExecuteAction(cornerAction(hc, "click_"+hc.Name), tr, tr.ActiveWorkspace())
+	}
+}
+
+func cornerAction(hc *store.Corner, name string) string {
+	screens := store.Workplace.Displays.Screens
+
+	// Obtain screen specific action
+	if int(hc.Screen) < len(screens) {
+		if corners, ok := common.Config.Screens[screens[hc.Screen].Name]; ok {
+			if action, ok := corners[name]; ok {
+				return action
+			}
+		}
+	}
+
+	return common.Config.Corners[name]
+}
+
+func updatePreview(tr *desktop.Tracker) {
+	move, insert, swap, screen := *tr.Handlers.MoveClient, *tr.Handlers.InsertClient, *tr.Handlers.SwapClient, *tr.Handlers.SwapScreen
+
+	// Close preview if not dragging
+	if !move.Active() || !move.Dragging || !store.Pointer.Pressed() {
+		ui.ClosePreview()
+		return
+	}
+
+	// Obtain geometry of target slot
+	var geom *common.Geometry
+	if c, ok := insert.Source.(*store.Client); ok {
+		geom = slotGeometry(tr, c, insert.Target.(string))
+	} else if co, ok := swap.Target.(*store.Client); ok {
+		geom = clientGeometry(co)
+	} else if ws, ok := screen.Target.(*desktop.Workspace); ok && ws != nil {
+		geom = store.DesktopGeometry(ws.Location.Screen)
+	}
+
+	// Show preview of target slot
+	if geom == nil {
+		ui.ClosePreview()
+		return
+	}
+	ui.ShowPreview(*geom)
+}
+
+func slotGeometry(tr *desktop.Tracker, c *store.Client, zone string) *common.Geometry {
+	ws := tr.WorkspaceAt(store.Workplace.CurrentDesktop, store.ScreenGet(store.Pointer.Position))
+	if ws == nil {
+		return nil
+	}
+	mg := ws.ActiveLayout().GetManager()
+
+	// Obtain first master or first slave
+	clients := mg.Masters.Stacked
+	if zone == "slave" {
+		clients = mg.Slaves.Stacked
+	}
+	for _, co := range clients {
+		if co != c {
+			return clientGeometry(co)
+		}
+	}
+
+	return store.DesktopGeometry(ws.Location.Screen)
+}
 
-	// Execute action
-	ExecuteAction(common.Config.Corners[hc.Name], tr, tr.ActiveWorkspace())
+func clientGeometry(c *store.Client) *common.Geometry {
+	x, y, w, h := c.OuterGeometry()
+	return &common.Geometry{X: x, Y: y, Width: w, Height: h}
 }
 
 func updateFocus(tr *desktop.Tracker) {
@@ -90,6 +350,15 @@ func updateFocus(tr *desktop.Tracker) {
 		return
 	}
 
+	// Ignore pointer movement caused by warp
+	if warp != nil {
+		warped := *warp == store.Pointer.Position
+		warp = nil
+		if warped {
+			return
+		}
+	}
+
 	// Ignore untracked clients
 	active := tr.ActiveClient()
 	hovered := tr.ClientAt(ws, store.Pointer.Position)
@@ -117,6 +386,58 @@ func updateFocus(tr *desktop.Tracker) {
 	})
 }
 
+func warpPointer(tr *desktop.Tracker, active xproto.Window, geom common.Geometry) {
+	if !common.Config.WindowFocusWarp {
+		return
+	}
+
+	// Wait for focus and tiling events
+	time.AfterFunc(150*time.Millisecond, func() {
+		store.Enqueue(func() {
+			warpActive(tr, active, geom)
+		})
+	})
+}
+
+func warpActive(tr *desktop.Tracker, active xproto.Window, geom common.Geometry) {
+
+	// Ignore unchanged and unmoved active client
+	w, g := activeGeometry(tr)
+	if w == 0 || (w == active && g == geom) {
+		return
+	}
+	c := tr.ActiveClient()
+
+	// Ignore pointer inside active client
+	if common.IsInsideRect(store.PointerGet(store.X).Position, g) {
+		return
+	}
+	log.Info("Warp pointer to active window [", c.Latest.Class, "]")
+
+	// Cancel pending hover event
+	if hover != nil {
+		hover.Stop()
+		hover = nil
+	}
+
+	// Move pointer to client center
+	center := g.Center()
+	warp = &center
+	store.PointerSet(store.X, center)
+}
+
+func activeGeometry(tr *desktop.Tracker) (xproto.Window, common.Geometry) {
+	c := tr.ActiveClient()
+	if c == nil {
+		return 0, common.Geometry{}
+	}
+
+	// Obtain active client window and geometry
+	x, y, w, h := c.OuterGeometry()
+
+	return c.Window.Id, common.Geometry{X: x, Y: y, Width: w, Height: h}
+}
+
 func poll(t time.Duration, fun func()) {
 	go func() {
 		for range time.Tick(t * time.Millisecond) {
@@ -0,0 +1,427 @@
+package input
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/desktop"
+	"github.com/leukipp/cortile/v2/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	actionName  *regexp.Regexp = regexp.MustCompile(`^[a-z][a-z_]*$`) // Pattern of parameterized action names
+	layoutNames []string                                              // Names of available layouts
+)
+
+var (
+	simpleActions = []string{"enable", "disable", "toggle", "decoration", "restore", "reset", "cycle_next", "cycle_previous", "layout_vertical_left", "layout_vertical_right", "layout_horizontal_top", "layout_horizontal_bottom", "layout_autotile", "autotile_toggle", "layout_maximized", "layout_fullscreen", "slave_increase", "slave_decrease", "master_increase", "master_decrease", "column_increase", "column_decrease", "window_next", "window_previous", "focus_last", "focus_mru_next", "focus_mru_previous", "focus_urgent", "window_hints", "window_hints_swap", "focus_left", "focus_right", "focus_up", "focus_down", "swap_left", "swap_right", "swap_up", "swap_down", "screen_next", "screen_previous", "mode_exit", "workspace_swap_screens", "desktop_next", "desktop_previous", "desktop_back_and_forth", "window_float_toggle", "master_make", "master_make_next", "master_make_previous", "proportion_increase", "proportion_decrease", "restart", "exit"} // Names of actions without argument
+)
+
+const (
+	runeText  = iota // Literal character inside quotes or escaped
+	runePlain        // Unquoted character
+	runeQuote        // Quote or escape character
+)
+
+type Action struct {
+	Name     string         // Action name
+	Argument string         // Raw action argument
+	Number   float64        // Numeric argument value
+	Relative bool           // Numeric argument is relative (+/-)
+	Field    string         // Field of key-value argument
+	Pattern  *regexp.Regexp // Pattern of key-value argument
+}
+
+type Step struct {
+	Action    string        // Action to execute
+	Delay     time.Duration // Delay before next step
+	Condition *Condition    // Condition to execute action
+}
+
+type Condition struct {
+	Field   string         // Field to compare
+	Negate  bool           // Condition is negated (!=)
+	Pattern *regexp.Regexp // Pattern of expected value
+}
+
+func IsMacro(action string) bool {
+	if _, named := common.Config.Macros[action]; named {
+		return true
+	}
+
+	// Check for unquoted step or condition separators
+	if len(splitQuoted(action, ";", -1)) < 2 && len(splitQuoted(action, " ? ", 2)) < 2 {
+		return false
+	}
+
+	// Treat only chains of known actions as macro
+	_, err := parseSteps(action, map[string]bool{}, true)
+	return err == nil
+}
+
+func IsAction(action string) bool {
+	if a, err := ParseAction(action); err != nil || a != nil {
+		return err == nil
+	}
+	if _, named := common.Config.Macros[action]; named {
+		return true
+	}
+	if _, ok := desktopIndex(action, "desktop_", ""); ok {
+		return true
+	}
+	if _, ok := desktopIndex(action, "window_to_desktop_", "_follow"); ok {
+		return true
+	}
+	if _, ok := desktopIndex(action, "window_to_desktop_", ""); ok {
+		return true
+	}
+	if _, ok := common.Config.Modes[strings.TrimPrefix(action, "mode_")]; ok && strings.HasPrefix(action, "mode_") {
+		return true
+	}
+	return common.IsInList(action, simpleActions)
+}
+
+func ParseMacro(macro string) ([]*Step, error) {
+	return parseSteps(macro, map[string]bool{}, false)
+}
+
+func parseSteps(macro string, visited map[string]bool, strict bool) ([]*Step, error) {
+	steps := []*Step{}
+
+	// Expand named macro
+	if m, ok := common.Config.Macros[macro]; ok {
+		if visited[macro] {
+			return nil, fmt.Errorf("recursive macro %s", macro)
+		}
+		visited[macro] = true
+		defer delete(visited, macro)
+		macro, strict = m, false
+	}
+
+	for _, text := range splitQuoted(macro, ";", -1) {
+		text = strings.TrimSpace(text)
+		if len(text) == 0 {
+			continue
+		}
+		step := &Step{Action: text}
+
+		// Parse delay step
+		if value, ok := strings.CutPrefix(text, "sleep:"); ok {
+			delay, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || delay < 0 {
+				return nil, fmt.Errorf("invalid delay %s", value)
+			}
+			steps = append(steps, &Step{Delay: time.Duration(delay) * time.Millisecond})
+			continue
+		}
+
+		// Parse conditional step
+		if parts := splitQuoted(text, " ? ", 2); len(parts) == 2 {
+			c, err := parseCondition(strings.TrimSpace(parts[0]))
+			if err != nil {
+				return nil, err
+			}
+			step.Action, step.Condition = strings.TrimSpace(parts[1]), c
+		}
+
+		// Expand nested named macro
+		if _, ok := common.Config.Macros[step.Action]; ok {
+			nested, err := parseSteps(step.Action, visited, false)
+			if err != nil {
+				return nil, err
+			}
+			for _, n := range nested {
+				if n.Condition == nil {
+					n.Condition = step.Condition
+				}
+			}
+			steps = append(steps, nested...)
+			continue
+		}
+
+		// Validate step action
+		if _, err := ParseAction(step.Action); err != nil {
+			return nil, err
+		}
+		if strict && !IsAction(step.Action) {
+			return nil, fmt.Errorf("unknown action %s", step.Action)
+		}
+		steps = append(steps, step)
+	}
+
+	return steps, nil
+}
+
+func parseCondition(condition string) (*Condition, error) {
+	fields := []string{"layout", "tiling", "class", "name", "desktop", "screen"}
+
+	// Split field and value
+	c := &Condition{}
+	field, value, ok := strings.Cut(condition, "!=")
+	if ok {
+		c.Negate = true
+	} else {
+		field, value, ok = strings.Cut(condition, "=")
+	}
+	field, value = strings.TrimSpace(field), strings.TrimSpace(value)
+	if !ok || !common.IsInList(field, fields) {
+		return nil, fmt.Errorf("invalid condition %s, expected <%s>=<value>", condition, strings.Join(fields, "|"))
+	}
+	c.Field = field
+
+	// Compile case insensitive pattern
+	reg, err := regexp.Compile("(?i)^(" + value + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %s in condition: %s", value, err)
+	}
+	c.Pattern = reg
+
+	return c, nil
+}
+
+func ParseAction(action string) (*Action, error) {
+	name, argument, found := strings.Cut(strings.TrimSpace(action), ":")
+	if !found || !actionName.MatchString(name) {
+		return nil, nil
+	}
+	a := &Action{Name: name, Argument: strings.TrimSpace(argument)}
+	if len(a.Argument) == 0 {
+		return nil, fmt.Errorf("missing argument for %s", name)
+	}
+
+	// Validate action argument
+	switch name {
+	case "layout":
+		return a, a.parseLayout()
+	case "gap":
+		return a, a.parseNumber(0, 1000, true)
+	case "proportion":
+		return a, a.parseNumber(0, 1, false)
+	case "desktop":
+		return a, a.parseNumber(1, 1000, true)
+	case "master_count":
+		return a, a.parseNumber(0, float64(common.Config.WindowMastersMax), true)
+	case "slave_count":
+		return a, a.parseNumber(0, float64(common.Config.WindowSlavesMax), true)
+	case "column":
+		return a, a.parseNumber(1, float64(common.Config.AutotileColumnsMax), true)
+	case "focus":
+		return a, a.parsePattern([]string{"class", "name"})
+	case "script":
+		return a, a.parseScript()
+	}
+
+	return nil, fmt.Errorf("unknown action %s", name)
+}
+
+func ValidateActions() {
+	actions := []string{}
+
+	// Obtain configured actions
+	for a := range common.Config.Keys {
+		actions = append(actions, a)
+	}
+	for _, keys := range common.Config.Modes {
+		for a := range keys {
+			actions = append(actions, a)
+		}
+	}
+	for button, a := range common.Config.Mouse {
+		if button != "modifier" && a != "move" && a != "resize" {
+			actions = append(actions, a)
+		}
+	}
+	for _, a := range common.Config.Corners {
+		actions = append(actions, a)
+	}
+	for _, corners := range common.Config.Screens {
+		for _, a := range corners {
+			actions = append(actions, a)
+		}
+	}
+	for _, a := range common.Config.Systray {
+		actions = append(actions, a)
+	}
+	for _, entry := range common.Config.TilingIcon {
+		actions = append(actions, entry[0])
+	}
+
+	for _, m := range common.Config.Macros {
+		actions = append(actions, m)
+	}
+
+	// Report invalid macros and parameterized actions
+	for _, a := range actions {
+		if err := ValidateAction(a); err != nil {
+			log.Warn("Error parsing action ", a, ": ", err)
+		}
+	}
+}
+
+func ValidateAction(action string) error {
+	var err error
+	if IsMacro(action) {
+		_, err = ParseMacro(action)
+	} else {
+		_, err = ParseAction(action)
+	}
+	return err
+}
+
+func (a *Action) parseLayout() error {
+	if len(layoutNames) == 0 {
+		for _, l := range desktop.CreateLayouts(store.Location{}) {
+			layoutNames = append(layoutNames, l.GetName())
+		}
+	}
+
+	// Validate layout name
+	if !common.IsInList(a.Argument, layoutNames) {
+		return fmt.Errorf("unknown layout %s, expected one of %s", a.Argument, strings.Join(layoutNames, "|"))
+	}
+
+	return nil
+}
+
+func (a *Action) parseNumber(min float64, max float64, integer bool) error {
+	a.Relative = strings.HasPrefix(a.Argument, "+") || strings.HasPrefix(a.Argument, "-")
+
+	// Parse numeric value
+	number, err := strconv.ParseFloat(a.Argument, 64)
+	if err != nil || math.IsNaN(number) || math.IsInf(number, 0) || (integer && number != float64(int(number))) {
+		return fmt.Errorf("invalid number %s for %s", a.Argument, a.Name)
+	}
+	a.Number = number
+
+	// Validate absolute value
+	if !a.Relative && (number < min || number > max) {
+		return fmt.Errorf("number %s for %s is out of range [%v, %v]", a.Argument, a.Name, min, max)
+	}
+
+	return nil
+}
+
+func (a *Action) parsePattern(fields []string) error {
+	field, pattern, found := strings.Cut(a.Argument, "=")
+	if !found || !common.IsInList(field, fields) {
+		return fmt.Errorf("invalid argument %s for %s, expected %s=<regex>", a.Argument, a.Name, strings.Join(fields, "|"))
+	}
+	a.Field = field
+
+	// Compile case insensitive pattern
+	reg, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern %s for %s: %s", pattern, a.Name, err)
+	}
+	a.Pattern = reg
+
+	return nil
+}
+
+func (a *Action) parseScript() error {
+	if !IsScript(a.Argument) {
+		return fmt.Errorf("unknown script action %s", a.Argument)
+	}
+	return nil
+}
+
+func ParseCommand(command string) ([]string, error) {
+	args := []string{}
+
+	// Split command into shell-style quoted arguments
+	var arg strings.Builder
+	found := false
+	err := scanQuoted(command, func(i int, r rune, kind int) {
+		switch {
+		case kind == runePlain && (r == ' ' || r == '\t' || r == '\n'):
+			if found {
+				args = append(args, arg.String())
+				arg.Reset()
+				found = false
+			}
+		case kind == runeQuote:
+			found = true
+		default:
+			found = true
+			arg.WriteRune(r)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s in %s", err, command)
+	}
+	if found {
+		args = append(args, arg.String())
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+
+	return args, nil
+}
+
+func splitQuoted(text string, sep string, n int) []string {
+	parts := []string{}
+
+	// Split text at separators outside of quotes
+	start := 0
+	scanQuoted(text, func(i int, r rune, kind int) {
+		if kind != runePlain || i < start || (n >= 0 && len(parts) >= n-1) {
+			return
+		}
+		if strings.HasPrefix(text[i:], sep) {
+			parts = append(parts, text[start:i])
+			start = i + len(sep)
+		}
+	})
+
+	return append(parts, text[start:])
+}
+
+func scanQuoted(text string, fun func(int, rune, int)) error {
+	var quote rune
+	escaped := false
+
+	// Classify characters using shell-style quoting rules
+	for i, r := range text {
+		switch {
+		case escaped:
+			if quote == '"' && r != '"' && r != '\\' && r != '$' && r != '`' {
+				fun(i, '\\', runeText)
+			}
+			fun(i, r, runeText)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			fun(i, r, runeQuote)
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				fun(i, r, runeQuote)
+				quote = 0
+			} else {
+				fun(i, r, runeText)
+			}
+		case r == '\'' || r == '"':
+			fun(i, r, runeQuote)
+			quote = r
+		default:
+			fun(i, r, runePlain)
+		}
+	}
+
+	// Validate unterminated quotes and escapes
+	if quote != 0 {
+		return fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return fmt.Errorf("unterminated escape")
+	}
+
+	return nil
+}
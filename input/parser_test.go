@@ -0,0 +1,53 @@
+package input
+
+import (
+	"testing"
+
+	"github.com/leukipp/cortile/v2/common"
+)
+
+func TestParseActionNumber(t *testing.T) {
+	common.Config.WindowMastersMax = 3
+
+	tests := []struct {
+		action   string  // Action to parse
+		valid    bool    // Action is expected to be valid
+		number   float64 // Expected numeric value
+		relative bool    // Expected relative flag
+	}{
+		{"gap:8", true, 8, false},
+		{"gap:+4", true, 4, true},
+		{"gap:-4", true, -4, true},
+		{"gap:1001", false, 0, false},
+		{"gap:2.5", false, 0, false},
+		{"gap:abc", false, 0, false},
+		{"proportion:0.6", true, 0.6, false},
+		{"proportion:+0.05", true, 0.05, true},
+		{"proportion:1.5", false, 0, false},
+		{"master_count:3", true, 3, false},
+		{"master_count:4", false, 0, false},
+		{"proportion:NaN", false, 0, false},
+		{"proportion:Inf", false, 0, false},
+		{"proportion:+Inf", false, 0, false},
+		{"proportion:-Inf", false, 0, false},
+		{"gap:+Inf", false, 0, false},
+		{"desktop:-NaN", false, 0, false},
+	}
+
+	for _, tt := range tests {
+		a, err := ParseAction(tt.action)
+		if !tt.valid {
+			if err == nil {
+				t.Errorf("expected error for %s", tt.action)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %s: %s", tt.action, err)
+			continue
+		}
+		if a.Number != tt.number || a.Relative != tt.relative {
+			t.Errorf("unexpected number %v (relative %v) for %s", a.Number, a.Relative, tt.action)
+		}
+	}
+}
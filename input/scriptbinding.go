@@ -0,0 +1,441 @@
+package input
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+
+	"path/filepath"
+
+	"github.com/jezek/xgb/xproto"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/desktop"
+	"github.com/leukipp/cortile/v2/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	scripts     *Scripts     // Loaded script actions and handlers
+	scriptDepth atomic.Int32 // Depth of nested script executions
+)
+
+var (
+	scriptSteps    uint64 = 10000000 // Maximum execution steps of a script call
+	scriptDepthMax int32  = 8        // Maximum depth of nested script executions
+)
+
+type Scripts struct {
+	Tracker  *desktop.Tracker               // Workspace tracker instance
+	Actions  map[string]starlark.Callable   // Named script actions
+	Handlers map[string][]starlark.Callable // Script event handlers
+}
+
+func BindScripts(tr *desktop.Tracker) {
+	scripts = &Scripts{
+		Tracker:  tr,
+		Actions:  make(map[string]starlark.Callable),
+		Handlers: make(map[string][]starlark.Callable),
+	}
+
+	// Read script files in config folder
+	configFolderPath := common.ConfigFolderPath(common.Build.Name)
+	files, err := filepath.Glob(filepath.Join(configFolderPath, "*.star"))
+	if err != nil {
+		log.Warn("Error reading scripts: ", configFolderPath)
+		return
+	}
+
+	// Load script files
+	for _, file := range files {
+		log.Info("Load script ", file)
+
+		thread := scriptThread(file)
+		_, err := starlark.ExecFileOptions(&syntax.FileOptions{
+			Set:             true,
+			While:           true,
+			TopLevelControl: true,
+			GlobalReassign:  true,
+		}, thread, file, nil, scriptApi())
+		if err != nil {
+			log.Warn("Error loading script ", file, ": ", err)
+		}
+	}
+}
+
+func IsScript(name string) bool {
+	if scripts == nil {
+		return false
+	}
+	_, ok := scripts.Actions[name]
+	return ok
+}
+
+func ExecuteScript(tr *desktop.Tracker, ws *desktop.Workspace, name string) bool {
+	if !IsScript(name) {
+		log.Warn("Unknown script action ", name)
+		return false
+	}
+
+	// Prevent endless nested executions
+	defer scriptDepth.Add(-1)
+	if scriptDepth.Add(1) > scriptDepthMax {
+		log.Warn("Error executing script action ", name, ": nested too deep")
+		return false
+	}
+
+	// Call script action with optional workspace argument
+	fn := scripts.Actions[name]
+	args := starlark.Tuple{}
+	if f, ok := fn.(*starlark.Function); ok && f.NumParams() > 0 {
+		args = append(args, scriptValue(reflect.ValueOf(workspaceInfo(ws))))
+	}
+	value, err := starlark.Call(scriptThread(name), fn, args, nil)
+	if err != nil {
+		log.Warn("Error executing script action ", name, ": ", err)
+		return false
+	}
+
+	// Treat anything but an explicit false as success
+	return value != starlark.False
+}
+
+func executeScriptHandlers(name string, ws *desktop.Workspace, w xproto.Window, c *store.Client, action string) {
+	if scripts == nil || len(scripts.Handlers[name]) == 0 {
+		return
+	}
+
+	// Obtain handler context
+	context := starlark.NewDict(10)
+	context.SetKey(starlark.String("Event"), starlark.String(name))
+	context.SetKey(starlark.String("Action"), starlark.String(action))
+	if ws != nil {
+		context.SetKey(starlark.String("Desktop"), starlark.MakeUint(ws.Location.Desktop))
+		context.SetKey(starlark.String("Screen"), starlark.MakeUint(ws.Location.Screen))
+		context.SetKey(starlark.String("Workspace"), starlark.String(ws.Name))
+		context.SetKey(starlark.String("Layout"), starlark.String(ws.ActiveLayout().GetName()))
+		context.SetKey(starlark.String("Tiling"), starlark.Bool(ws.TilingEnabled()))
+	}
+	if w != 0 {
+		context.SetKey(starlark.String("WindowId"), starlark.MakeUint(uint(w)))
+	}
+	if c != nil && c.Latest != nil {
+		context.SetKey(starlark.String("WindowClass"), starlark.String(c.Latest.Class))
+		context.SetKey(starlark.String("WindowName"), starlark.String(c.Latest.Name))
+	}
+	context.Freeze()
+
+	// Run event handlers on the X event loop
+	for _, fn := range scripts.Handlers[name] {
+		fn := fn
+		call := func() {
+			if _, err := starlark.Call(scriptThread(name), fn, starlark.Tuple{context}, nil); err != nil {
+				log.Warn("Error executing script handler ", name, ": ", err)
+			}
+		}
+
+		// Run exit handlers in place, the event loop stops afterwards
+		if name == "exit" {
+			call()
+			continue
+		}
+		store.Enqueue(call)
+	}
+}
+
+func scriptThread(name string) *starlark.Thread {
+	thread := &starlark.Thread{
+		Name: name,
+		Print: func(thread *starlark.Thread, msg string) {
+			log.Info("Script ", thread.Name, ": ", msg)
+		},
+	}
+	thread.SetMaxExecutionSteps(scriptSteps)
+	return thread
+}
+
+func scriptApi() starlark.StringDict {
+	return starlark.StringDict{
+		"action":          starlark.NewBuiltin("action", scriptAction),
+		"on":              starlark.NewBuiltin("on", scriptOn),
+		"clients":         starlark.NewBuiltin("clients", scriptClients),
+		"focused":         starlark.NewBuiltin("focused", scriptFocused),
+		"workspace":       starlark.NewBuiltin("workspace", scriptWorkspace),
+		"layout":          starlark.NewBuiltin("layout", scriptLayout),
+		"run":             starlark.NewBuiltin("run", scriptRun),
+		"move":            starlark.NewBuiltin("move", scriptMove),
+		"move_to_desktop": starlark.NewBuiltin("move_to_desktop", scriptMoveToDesktop),
+		"move_to_screen":  starlark.NewBuiltin("move_to_screen", scriptMoveToScreen),
+		"set_layout":      starlark.NewBuiltin("set_layout", scriptSetLayout),
+		"set_proportions": starlark.NewBuiltin("set_proportions", scriptSetProportions),
+	}
+}
+
+func scriptAction(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	var fn starlark.Callable
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "name", &name, "fn", &fn); err != nil {
+		return nil, err
+	}
+
+	// Register named action
+	if !actionName.MatchString(name) {
+		return nil, fmt.Errorf("%s: invalid name %s", b.Name(), name)
+	}
+	scripts.Actions[name] = fn
+
+	return starlark.None, nil
+}
+
+func scriptOn(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var event string
+	var fn starlark.Callable
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "event", &event, "fn", &fn); err != nil {
+		return nil, err
+	}
+
+	// Register event handler
+	if !common.IsInList(event, hookEvents) {
+		return nil, fmt.Errorf("%s: unknown event %s, expected one of %s", b.Name(), event, strings.Join(hookEvents, "|"))
+	}
+	scripts.Handlers[event] = append(scripts.Handlers[event], fn)
+
+	return starlark.None, nil
+}
+
+func scriptClients(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs); err != nil {
+		return nil, err
+	}
+
+	// Obtain tracked and floated clients
+	clients := clientInfos(scripts.Tracker)
+
+	return scriptValue(reflect.ValueOf(clients)), nil
+}
+
+func scriptFocused(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs); err != nil {
+		return nil, err
+	}
+	tr := scripts.Tracker
+
+	// Obtain active client
+	c := tr.ActiveClient()
+	if c == nil {
+		return starlark.None, nil
+	}
+
+	return scriptValue(reflect.ValueOf(clientInfo(c, tr))), nil
+}
+
+func scriptWorkspace(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	desktop, screen := -1, -1
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "desktop?", &desktop, "screen?", &screen); err != nil {
+		return nil, err
+	}
+
+	// Obtain workspace
+	ws, err := scriptWorkspaceAt(b, desktop, screen)
+	if err != nil {
+		return nil, err
+	}
+
+	return scriptValue(reflect.ValueOf(workspaceInfo(ws))), nil
+}
+
+func scriptLayout(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	desktop, screen := -1, -1
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "desktop?", &desktop, "screen?", &screen); err != nil {
+		return nil, err
+	}
+
+	// Obtain workspace
+	ws, err := scriptWorkspaceAt(b, desktop, screen)
+	if err != nil {
+		return nil, err
+	}
+
+	return scriptValue(reflect.ValueOf(layoutInfo(ws))), nil
+}
+
+func scriptRun(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var action string
+	desktop, screen := -1, -1
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "action", &action, "desktop?", &desktop, "screen?", &screen); err != nil {
+		return nil, err
+	}
+
+	// Validate action
+	if err := ValidateAction(action); err != nil {
+		return nil, fmt.Errorf("%s: %s", b.Name(), err)
+	}
+
+	// Execute action
+	ws, err := scriptWorkspaceAt(b, desktop, screen)
+	if err != nil {
+		return nil, err
+	}
+
+	return starlark.Bool(ExecuteAction(action, scripts.Tracker, ws)), nil
+}
+
+func scriptMove(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var id, x, y, width, height int
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "id", &id, "x", &x, "y", &y, "width?", &width, "height?", &height); err != nil {
+		return nil, err
+	}
+
+	// Move client window
+	c, err := scriptClient(b, id)
+	if err != nil {
+		return nil, err
+	}
+	c.MoveWindow(x, y, width, height)
+
+	return starlark.True, nil
+}
+
+func scriptMoveToDesktop(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var id, desktop int
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "id", &id, "desktop", &desktop); err != nil {
+		return nil, err
+	}
+
+	// Move client window to desktop
+	c, err := scriptClient(b, id)
+	if err != nil {
+		return nil, err
+	}
+	if desktop < 0 || uint(desktop) >= store.Workplace.DesktopCount {
+		return nil, fmt.Errorf("%s: invalid desktop %d", b.Name(), desktop)
+	}
+
+	return starlark.Bool(c.MoveToDesktop(uint32(desktop))), nil
+}
+
+func scriptMoveToScreen(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var id, screen int
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "id", &id, "screen", &screen); err != nil {
+		return nil, err
+	}
+
+	// Move client window to screen
+	c, err := scriptClient(b, id)
+	if err != nil {
+		return nil, err
+	}
+	if screen < 0 || uint(screen) >= store.Workplace.ScreenCount {
+		return nil, fmt.Errorf("%s: invalid screen %d", b.Name(), screen)
+	}
+
+	return starlark.Bool(c.MoveToScreen(uint32(screen))), nil
+}
+
+func scriptSetLayout(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	desktop, screen := -1, -1
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "name", &name, "desktop?", &desktop, "screen?", &screen); err != nil {
+		return nil, err
+	}
+
+	// Activate layout by name
+	ws, err := scriptWorkspaceAt(b, desktop, screen)
+	if err != nil {
+		return nil, err
+	}
+
+	return starlark.Bool(SetLayout(scripts.Tracker, ws, name)), nil
+}
+
+func scriptSetProportions(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var kind string
+	var list *starlark.List
+	desktop, screen := -1, -1
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "kind", &kind, "values", &list, "desktop?", &desktop, "screen?", &screen); err != nil {
+		return nil, err
+	}
+
+	// Convert proportion values
+	values := []float64{}
+	for i := 0; i < list.Len(); i++ {
+		value, ok := starlark.AsFloat(list.Index(i))
+		if !ok {
+			return nil, fmt.Errorf("%s: invalid proportion %s", b.Name(), list.Index(i))
+		}
+		values = append(values, value)
+	}
+
+	// Update proportions
+	ws, err := scriptWorkspaceAt(b, desktop, screen)
+	if err != nil {
+		return nil, err
+	}
+	if err := setProportions(scripts.Tracker, ws, kind, values); err != nil {
+		return nil, fmt.Errorf("%s: %s", b.Name(), err)
+	}
+
+	return starlark.True, nil
+}
+
+func scriptWorkspaceAt(b *starlark.Builtin, desktop int, screen int) (*desktop.Workspace, error) {
+	tr := scripts.Tracker
+
+	// Obtain active or given workspace
+	ws := tr.ActiveWorkspace()
+	if desktop >= 0 || screen >= 0 {
+		if desktop < 0 {
+			desktop = int(store.Workplace.CurrentDesktop)
+		}
+		if screen < 0 {
+			screen = int(store.Workplace.CurrentScreen)
+		}
+		ws = tr.WorkspaceAt(uint(desktop), uint(screen))
+	}
+	if ws == nil {
+		return nil, fmt.Errorf("%s: invalid workspace %d-%d", b.Name(), desktop, screen)
+	}
+
+	return ws, nil
+}
+
+func scriptClient(b *starlark.Builtin, id int) (*store.Client, error) {
+	c, ok := scripts.Tracker.Clients[xproto.Window(id)]
+	if !ok {
+		return nil, fmt.Errorf("%s: invalid client %d", b.Name(), id)
+	}
+	return c, nil
+}
+
+func scriptValue(v reflect.Value) starlark.Value {
+	switch v.Kind() {
+	case reflect.Bool:
+		return starlark.Bool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return starlark.MakeInt64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return starlark.MakeUint64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return starlark.Float(v.Float())
+	case reflect.String:
+		return starlark.String(v.String())
+	case reflect.Slice:
+		list := []starlark.Value{}
+		for i := 0; i < v.Len(); i++ {
+			list = append(list, scriptValue(v.Index(i)))
+		}
+		return starlark.NewList(list)
+	case reflect.Struct:
+		dict := starlark.NewDict(v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			dict.SetKey(starlark.String(v.Type().Field(i).Name), scriptValue(v.Field(i)))
+		}
+		return dict
+	}
+	return starlark.None
+}
@@ -0,0 +1,296 @@
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"encoding/json"
+
+	"golang.org/x/exp/maps"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/desktop"
+	"github.com/leukipp/cortile/v2/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	listener    net.Listener         // Socket listener
+	subscribers map[*Subscriber]bool // Socket event subscribers
+	subscribeMu sync.Mutex           // Lock for socket event subscribers
+)
+
+type Request struct {
+	Command string   // Command name
+	Args    []string // Command arguments
+}
+
+type Subscriber struct {
+	Conn   net.Conn    // Subscribed socket connection
+	Events []string    // Subscribed event names
+	Queue  chan string // Queue of pending event lines
+	Lock   sync.Mutex  // Lock for connection writes
+}
+
+func BindSocket(tr *desktop.Tracker) {
+	if common.HasFlag("disable-socket-interface") {
+		return
+	}
+
+	// Remove stale socket file
+	os.Remove(common.Args.Socket)
+
+	// Listen on socket file
+	var err error
+	listener, err = net.Listen("unix", common.Args.Socket)
+	if err != nil {
+		log.Warn("Error initializing socket server: ", err)
+		return
+	}
+	os.Chmod(common.Args.Socket, 0600)
+
+	go serve(listener, tr)
+}
+
+func CloseSocket() {
+	if listener == nil {
+		return
+	}
+
+	// Close listener and remove socket file
+	listener.Close()
+	os.Remove(common.Args.Socket)
+	listener = nil
+}
+
+func Message(args []string) {
+	conn, err := net.Dial("unix", common.Args.Socket)
+	if err != nil {
+		fatal("Error connecting socket server", err)
+	}
+	defer conn.Close()
+
+	// Send request
+	data, _ := json.Marshal(Request{Command: args[0], Args: args[1:]})
+	if _, err := conn.Write(append(data, '\n')); err != nil {
+		fatal("Error sending socket request", err)
+	}
+
+	// Print replies
+	reader := bufio.NewScanner(conn)
+	reader.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for reader.Scan() {
+		fmt.Println(reader.Text())
+		if args[0] != "subscribe" {
+			return
+		}
+	}
+}
+
+func Publish(name string, obj interface{}) {
+	subscribeMu.Lock()
+	defer subscribeMu.Unlock()
+	streams := addonStreams()
+	if len(subscribers) == 0 && len(streams) == 0 {
+		return
+	}
+
+	// Send event to subscribers
+	line := dataMap("Event", name, structToMap(obj)) + "\n"
+	for s := range subscribers {
+		if len(s.Events) > 0 && !common.IsInList(name, s.Events) {
+			continue
+		}
+		select {
+		case s.Queue <- line:
+		default:
+			log.Warn("Drop event for socket subscriber ", s.Conn.RemoteAddr())
+		}
+	}
+
+	// Send event to stdio addons
+	for _, events := range streams {
+		select {
+		case events <- line:
+		default:
+		}
+	}
+}
+
+func serve(l net.Listener, tr *desktop.Tracker) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go handle(conn, tr)
+	}
+}
+
+func handle(conn net.Conn, tr *desktop.Tracker) {
+	s := &Subscriber{Conn: conn}
+	defer unsubscribe(s)
+	defer conn.Close()
+
+	// Read line delimited requests
+	reader := bufio.NewScanner(conn)
+	for reader.Scan() {
+		var r Request
+		if err := json.Unmarshal(reader.Bytes(), &r); err != nil {
+			s.write(dataMap("Error", "Request", common.Map{"Message": err.Error()}) + "\n")
+			continue
+		}
+
+		// Subscribe to events
+		if r.Command == "subscribe" {
+			unsubscribe(s)
+			s.Events = r.Args
+			subscribe(s)
+			s.write(dataMap("Result", r.Command, common.Map{"Success": true, "Events": r.Args}) + "\n")
+			continue
+		}
+
+		// Reply to command executed on the X event loop
+		var line string
+		store.Execute(func() {
+			line = reply(r, tr)
+		})
+		s.write(line)
+	}
+}
+
+func reply(r Request, tr *desktop.Tracker) string {
+	data, err := command(r, tr)
+	if err != nil {
+		return dataMap("Error", r.Command, common.Map{"Message": err.Error()}) + "\n"
+	}
+	return dataMap("Result", r.Command, data) + "\n"
+}
+
+func command(r Request, tr *desktop.Tracker) (common.Map, error) {
+	switch r.Command {
+	case "action":
+		if len(r.Args) == 0 {
+			return nil, fmt.Errorf("missing action name")
+		}
+
+		// Validate action
+		if err := ValidateAction(r.Args[0]); err != nil {
+			return common.Map{"Success": false, "Error": err.Error()}, nil
+		}
+
+		// Execute action
+		ws, err := workspaceArg(r.Args[1:], tr)
+		if err != nil {
+			return nil, err
+		}
+		return common.Map{"Success": ExecuteAction(r.Args[0], tr, ws)}, nil
+	case "layout":
+		ws, err := workspaceArg(r.Args, tr)
+		if err != nil {
+			return nil, err
+		}
+		return structToMap(struct {
+			Name     string
+			Location interface{}
+			Enabled  bool
+			Manager  interface{}
+		}{
+			Name:     ws.ActiveLayout().GetName(),
+			Location: ws.Location,
+			Enabled:  ws.TilingEnabled(),
+			Manager:  ws.ActiveLayout().GetManager(),
+		}), nil
+	case "workspaces":
+		return common.Map{"Values": structToList(maps.Values(tr.Workspaces))}, nil
+	case "clients":
+		return common.Map{"Values": structToList(maps.Values(tr.Clients))}, nil
+	case "config":
+		return structToMap(common.Config), nil
+	}
+
+	return nil, fmt.Errorf("unknown command %s", r.Command)
+}
+
+func workspaceArg(args []string, tr *desktop.Tracker) (*desktop.Workspace, error) {
+	if len(args) == 0 {
+		ws := tr.ActiveWorkspace()
+		if ws == nil {
+			return nil, fmt.Errorf("no active workspace")
+		}
+		return ws, nil
+	}
+
+	// Parse desktop and screen arguments
+	if len(args) != 2 {
+		return nil, fmt.Errorf("expected desktop and screen arguments")
+	}
+	desktop, err1 := strconv.Atoi(args[0])
+	screen, err2 := strconv.Atoi(args[1])
+	if err1 != nil || err2 != nil || desktop < 0 || screen < 0 {
+		return nil, fmt.Errorf("invalid desktop %s or screen %s", args[0], args[1])
+	}
+
+	// Obtain workspace at location
+	ws := tr.WorkspaceAt(uint(desktop), uint(screen))
+	if ws == nil {
+		return nil, fmt.Errorf("invalid workspace %d-%d", desktop, screen)
+	}
+
+	return ws, nil
+}
+
+func subscribe(s *Subscriber) {
+	subscribeMu.Lock()
+	defer subscribeMu.Unlock()
+	if subscribers == nil {
+		subscribers = make(map[*Subscriber]bool)
+	}
+	subscribers[s] = true
+
+	// Write queued events until unsubscribed
+	s.Queue = make(chan string, 256)
+	go func(queue chan string) {
+		for line := range queue {
+			if err := s.write(line); err != nil {
+				s.Conn.Close()
+			}
+		}
+	}(s.Queue)
+}
+
+func unsubscribe(s *Subscriber) {
+	subscribeMu.Lock()
+	defer subscribeMu.Unlock()
+	if !subscribers[s] {
+		return
+	}
+	delete(subscribers, s)
+	close(s.Queue)
+}
+
+func (s *Subscriber) write(line string) error {
+	s.Lock.Lock()
+	defer s.Lock.Unlock()
+
+	// Write with timeout to skip stalled connections
+	s.Conn.SetWriteDeadline(time.Now().Add(time.Second))
+	_, err := s.Conn.Write([]byte(line))
+
+	return err
+}
+
+func structToList(obj interface{}) (value []interface{}) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return value
+	}
+	json.Unmarshal(data, &value)
+	return value
+}
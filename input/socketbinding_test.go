@@ -0,0 +1,201 @@
+package input
+
+import (
+	"bufio"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"encoding/json"
+	"path/filepath"
+
+	"github.com/jezek/xgb/xproto"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/desktop"
+	"github.com/leukipp/cortile/v2/store"
+)
+
+var (
+	loopOnce sync.Once // Start event loop replacement once
+)
+
+type socketReply struct {
+	Type string     // Reply type (Result, Error, Event)
+	Name string     // Command or event name
+	Data common.Map // Reply data
+}
+
+func TestSocketRoundTrip(t *testing.T) {
+	conn, reader := dialSocket(t)
+
+	// Execute action
+	r := socketRequest(t, conn, reader, `{"Command":"action","Args":["master_increase"]}`)
+	if r.Type != "Result" || r.Name != "action" || r.Data["Success"] != true {
+		t.Errorf("unexpected action reply %+v", r)
+	}
+
+	// Query changed layout
+	r = socketRequest(t, conn, reader, `{"Command":"layout"}`)
+	masters, _ := r.Data["Manager"].(map[string]interface{})["Masters"].(map[string]interface{})
+	if r.Type != "Result" || r.Name != "layout" || masters["Maximum"] != 2.0 {
+		t.Errorf("unexpected layout reply %+v", r)
+	}
+
+	// Execute invalid action
+	r = socketRequest(t, conn, reader, `{"Command":"action","Args":["layout:unknown"]}`)
+	if r.Type != "Result" || r.Data["Success"] != false || r.Data["Error"] == nil {
+		t.Errorf("unexpected invalid action reply %+v", r)
+	}
+
+	// Execute action on invalid workspace
+	r = socketRequest(t, conn, reader, `{"Command":"action","Args":["master_increase","9","9"]}`)
+	if r.Type != "Error" || r.Name != "action" {
+		t.Errorf("unexpected invalid workspace reply %+v", r)
+	}
+
+	// Query workspaces
+	r = socketRequest(t, conn, reader, `{"Command":"workspaces"}`)
+	values, _ := r.Data["Values"].([]interface{})
+	if r.Type != "Result" || r.Name != "workspaces" || len(values) != 1 {
+		t.Fatalf("unexpected workspaces reply %+v", r)
+	}
+	if name := values[0].(map[string]interface{})["Name"]; name != "workspace-0-0" {
+		t.Errorf("unexpected workspace name %v", name)
+	}
+
+	// Query clients
+	r = socketRequest(t, conn, reader, `{"Command":"clients"}`)
+	values, _ = r.Data["Values"].([]interface{})
+	if r.Type != "Result" || r.Name != "clients" || len(values) != 3 {
+		t.Fatalf("unexpected clients reply %+v", r)
+	}
+	for _, value := range values {
+		if latest := value.(map[string]interface{})["Latest"].(map[string]interface{}); latest["Class"] != "terminal" {
+			t.Errorf("unexpected client class %v", latest["Class"])
+		}
+	}
+}
+
+func TestSocketSubscribe(t *testing.T) {
+	conn, reader := dialSocket(t)
+
+	// Subscribe to single property
+	r := socketRequest(t, conn, reader, `{"Command":"subscribe","Args":["Action"]}`)
+	if r.Type != "Result" || r.Name != "subscribe" || r.Data["Success"] != true {
+		t.Fatalf("unexpected subscribe reply %+v", r)
+	}
+
+	// Receive subscribed property changes only
+	SetProperty("Workplace", *store.Workplace)
+	SetProperty("Action", common.Map{"Name": "cycle_next"})
+	r = socketRead(t, conn, reader)
+	if r.Type != "Event" || r.Name != "Action" || r.Data["Name"] != "cycle_next" {
+		t.Errorf("unexpected event %+v", r)
+	}
+}
+
+func TestSocketErrors(t *testing.T) {
+	conn, reader := dialSocket(t)
+
+	// Request unknown command
+	r := socketRequest(t, conn, reader, `{"Command":"unknown"}`)
+	if r.Type != "Error" || r.Name != "unknown" || r.Data["Message"] != "unknown command unknown" {
+		t.Errorf("unexpected unknown command reply %+v", r)
+	}
+
+	// Request malformed json
+	r = socketRequest(t, conn, reader, `{"Command":`)
+	if r.Type != "Error" || r.Name != "Request" || r.Data["Message"] == nil {
+		t.Errorf("unexpected malformed json reply %+v", r)
+	}
+
+	// Connection stays usable after errors
+	r = socketRequest(t, conn, reader, `{"Command":"workspaces"}`)
+	if r.Type != "Result" {
+		t.Errorf("unexpected reply after errors %+v", r)
+	}
+}
+
+func dialSocket(t *testing.T) (net.Conn, *bufio.Scanner) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	common.Args.Socket = filepath.Join(t.TempDir(), "cortile.sock")
+
+	// Create tracker without X connection
+	location := store.Location{Desktop: 0, Screen: 0}
+	common.Config.WindowMastersMax, common.Config.WindowSlavesMax = 2, 2
+	store.Workplace = &store.XWorkplace{DesktopCount: 1, ScreenCount: 1}
+	ws := &desktop.Workspace{Name: "workspace-0-0", Location: location, Layouts: desktop.CreateLayouts(location), Tiling: true}
+	tr := &desktop.Tracker{
+		Clients:    map[xproto.Window]*store.Client{},
+		Workspaces: map[store.Location]*desktop.Workspace{location: ws},
+		Deferred:   map[*desktop.Workspace]bool{}, // Skip tiling without X connection
+		Handlers: &desktop.Handlers{
+			ResizeClient: &desktop.Handler{},
+			MoveClient:   &desktop.Handler{},
+			SwapClient:   &desktop.Handler{},
+			SwapScreen:   &desktop.Handler{},
+			InsertClient: &desktop.Handler{},
+		},
+	}
+
+	// Add clients to workspace layout
+	for w := xproto.Window(1); w <= 3; w++ {
+		c := &store.Client{Window: &store.XWindow{Id: w}, Latest: &store.Info{Class: "terminal", Location: location}}
+		tr.Clients[w] = c
+		ws.ActiveLayout().AddClient(c)
+	}
+
+	// Run queued functions in place of the X event loop
+	loopOnce.Do(func() {
+		go func() {
+			for fun := range store.Calls {
+				fun()
+			}
+		}()
+	})
+
+	// Start socket server
+	BindSocket(tr)
+	if listener == nil {
+		t.Fatal("socket server not started")
+	}
+	t.Cleanup(CloseSocket)
+
+	// Connect to socket server
+	conn, err := net.Dial("unix", common.Args.Socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return conn, bufio.NewScanner(conn)
+}
+
+func socketRequest(t *testing.T, conn net.Conn, reader *bufio.Scanner, request string) socketReply {
+	t.Helper()
+
+	// Send line delimited request
+	if _, err := conn.Write([]byte(request + "\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	return socketRead(t, conn, reader)
+}
+
+func socketRead(t *testing.T, conn net.Conn, reader *bufio.Scanner) socketReply {
+	t.Helper()
+
+	// Read line delimited reply
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if !reader.Scan() {
+		t.Fatal("no reply: ", reader.Err())
+	}
+	var r socketReply
+	if err := json.Unmarshal(reader.Bytes(), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	return r
+}
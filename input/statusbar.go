@@ -0,0 +1,218 @@
+package input
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"encoding/json"
+	"text/template"
+
+	"github.com/godbus/dbus/v5"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/store"
+)
+
+var (
+	statusProperties = []string{"Workplace", "Workspaces", "Clients", "Windows", "Action"} // Properties used for status lines
+)
+
+type StatusInfo struct {
+	Desktop   uint   // Current desktop index
+	Screen    uint   // Current screen index
+	Workspace string // Workspace name
+	Layout    string // Active layout name
+	Tiling    bool   // Tiling is enabled
+	Masters   int    // Maximum number of masters
+	Slaves    int    // Maximum number of slaves
+	Clients   int    // Number of windows on workspace
+	Title     string // Focused window title
+	Class     string // Focused window class
+	Action    string // Last executed action
+}
+
+type StatusBlock struct {
+	Text      string   `json:"text"`       // Waybar text
+	Alt       string   `json:"alt"`        // Waybar alternative text
+	Tooltip   string   `json:"tooltip"`    // Waybar tooltip text
+	Class     []string `json:"class"`      // Waybar style classes
+	FullText  string   `json:"full_text"`  // I3blocks text
+	ShortText string   `json:"short_text"` // I3blocks short text
+}
+
+func Status(format string, js bool, screen int) {
+	conn, err := connect()
+	if err != nil {
+		fatal("Error initializing dbus server", err)
+	}
+	defer conn.Close()
+
+	// Parse format template
+	tmpl, err := template.New("status").Parse(format)
+	if err != nil {
+		fatal("Error parsing status format", err)
+	}
+
+	// Subscribe to property changes
+	err = conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(opath),
+		dbus.WithMatchInterface("org.freedesktop.DBus.Properties"),
+		dbus.WithMatchMember("PropertiesChanged"),
+	)
+	if err != nil {
+		fatal("Error subscribing dbus signals", err)
+	}
+	ch := make(chan *dbus.Signal, 10)
+	conn.Signal(ch)
+
+	// Receive initial properties
+	data := map[string]common.Map{}
+	for _, name := range statusProperties {
+		var reply dbus.Variant
+		call := conn.Object(iface, opath).Call("org.freedesktop.DBus.Properties.Get", 0, iface, name)
+		if call.Err == nil && call.Store(&reply) == nil {
+			data[name] = variantToMap(reply)
+		}
+	}
+
+	// Print status on changes
+	line := statusLine(tmpl, js, statusInfo(data, screen))
+	fmt.Println(line)
+	for signal := range ch {
+		if len(signal.Body) < 2 {
+			continue
+		}
+		changed, ok := signal.Body[1].(map[string]dbus.Variant)
+		if !ok {
+			continue
+		}
+
+		// Update changed properties
+		for name, variant := range changed {
+			if name == "Disconnect" {
+				data = map[string]common.Map{}
+			} else if common.IsInList(name, statusProperties) {
+				data[name] = variantToMap(variant)
+			}
+		}
+
+		// Print changed status line
+		if next := statusLine(tmpl, js, statusInfo(data, screen)); next != line {
+			line = next
+			fmt.Println(line)
+		}
+	}
+}
+
+func statusInfo(data map[string]common.Map, screen int) *StatusInfo {
+	var workplace store.XWorkplace
+	var windows store.XWindows
+	var action struct {
+		Name string
+	}
+	var workspaces struct {
+		Values []struct {
+			Name     string
+			Location store.Location
+			Layouts  []struct {
+				Name    string
+				Masters store.Clients
+				Slaves  store.Clients
+			}
+			Layout uint
+			Tiling bool
+		}
+	}
+	var clients struct {
+		Values []store.Client
+	}
+	mapToStruct(data["Workplace"], &workplace)
+	mapToStruct(data["Windows"], &windows)
+	mapToStruct(data["Action"], &action)
+	mapToStruct(data["Workspaces"], &workspaces)
+	mapToStruct(data["Clients"], &clients)
+
+	// Obtain workspace location
+	location := store.Location{Desktop: workplace.CurrentDesktop, Screen: workplace.CurrentScreen}
+	if screen >= 0 {
+		location.Screen = uint(screen)
+	}
+
+	// Obtain workspace at location
+	for _, ws := range workspaces.Values {
+		if ws.Location != location || int(ws.Layout) >= len(ws.Layouts) {
+			continue
+		}
+		layout := ws.Layouts[ws.Layout]
+		info := &StatusInfo{
+			Desktop:   location.Desktop,
+			Screen:    location.Screen,
+			Workspace: ws.Name,
+			Layout:    layout.Name,
+			Tiling:    ws.Tiling,
+			Masters:   layout.Masters.Maximum,
+			Slaves:    layout.Slaves.Maximum,
+			Action:    action.Name,
+		}
+
+		// Obtain workspace and focused clients
+		for _, c := range clients.Values {
+			if c.Window == nil || c.Latest == nil || c.Latest.Location != location {
+				continue
+			}
+			info.Clients += 1
+			if c.Window.Id == windows.Active.Id {
+				info.Title = c.Latest.Name
+				info.Class = c.Latest.Class
+			}
+		}
+
+		return info
+	}
+
+	return nil
+}
+
+func statusLine(tmpl *template.Template, js bool, info *StatusInfo) string {
+	text := ""
+
+	// Render format template
+	if info != nil {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, info); err != nil {
+			text = err.Error()
+		} else {
+			text = strings.ReplaceAll(buf.String(), "\n", " ")
+		}
+	}
+	if !js {
+		return text
+	}
+
+	// Render json block
+	block := StatusBlock{Text: text, FullText: text, Class: []string{}}
+	if info != nil {
+		block.Alt = info.Layout
+		block.ShortText = info.Layout
+		block.Tooltip = strings.TrimSpace(fmt.Sprintf("%s\n%s", info.Workspace, info.Title))
+		block.Class = []string{info.Layout, "disabled"}
+		if info.Tiling {
+			block.Class[1] = "enabled"
+		}
+	}
+	data, err := json.Marshal(block)
+	if err != nil {
+		return "{}"
+	}
+
+	return string(data)
+}
+
+func mapToStruct(obj common.Map, value interface{}) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return
+	}
+	json.Unmarshal(data, value)
+}
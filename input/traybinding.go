@@ -30,6 +30,7 @@ type Menu struct {
 	Toggle     *systray.MenuItem   // Toggle checkbox item
 	Decoration *systray.MenuItem   // Decoration checkbox item
 	Actions    []*systray.MenuItem // Actions for commands
+	Addons     []*systray.MenuItem // Addon status items
 }
 
 func BindTray(tr *desktop.Tracker) {
@@ -180,6 +181,26 @@ func items(tr *desktop.Tracker) {
 	// Menu items
 	menu = &Menu{}
 	systray.AddSeparator()
+
+	// Addon submenu
+	if list := Addons(); len(list) > 0 {
+		item := systray.AddMenuItem("Addons", "Addons")
+		for _, a := range list {
+			subitem := item.AddSubMenuItem(addonTitle(*a), a.Path)
+			menu.Addons = append(menu.Addons, subitem)
+
+			// Addon item click
+			go func(a *Addon) {
+				for {
+					<-subitem.ClickedCh
+
+					// Restart addon process
+					RestartAddon(a)
+				}
+			}(a)
+		}
+		systray.AddSeparator()
+	}
 	for _, entry := range common.Config.TilingIcon {
 		action, text := entry[0], entry[1]
 
@@ -217,6 +238,26 @@ func items(tr *desktop.Tracker) {
 	}
 }
 
+func updateAddonItems(values []Addon) {
+	if menu == nil {
+		return
+	}
+
+	// Update addon status titles
+	for i, item := range menu.Addons {
+		if i < len(values) {
+			item.SetTitle(addonTitle(values[i]))
+		}
+	}
+}
+
+func addonTitle(a Addon) string {
+	if a.Restarts > 0 {
+		return fmt.Sprintf("%s (%s, %d restarts)", a.Name, a.Status, a.Restarts)
+	}
+	return fmt.Sprintf("%s (%s)", a.Name, a.Status)
+}
+
 func messages(tr *desktop.Tracker) {
 	var destination string
 
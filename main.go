@@ -11,8 +11,6 @@ import (
 
 	"runtime/debug"
 
-	"github.com/jezek/xgbutil/xevent"
-
 	"github.com/leukipp/cortile/v2/common"
 	"github.com/leukipp/cortile/v2/desktop"
 	"github.com/leukipp/cortile/v2/input"
@@ -70,6 +68,12 @@ func main() {
 	// Run dbus instance
 	runDbus()
 
+	// Run msg instance
+	runMsg()
+
+	// Run status instance
+	runStatus()
+
 	// Run main instance
 	runMain()
 }
@@ -101,6 +105,30 @@ func runDbus() {
 	}
 }
 
+func runMsg() {
+	if len(common.Args.Msg.P) == 0 {
+		return
+	}
+
+	// Send socket message
+	input.Message(common.Args.Msg.P)
+
+	// Prevent main instance start
+	os.Exit(0)
+}
+
+func runStatus() {
+	if !common.Args.Status.Enabled {
+		return
+	}
+
+	// Print status lines
+	input.Status(common.Args.Status.Format, common.Args.Status.Json, common.Args.Status.Screen)
+
+	// Prevent main instance start
+	os.Exit(0)
+}
+
 func runMain() {
 	defer func() {
 		if err := recover(); err != nil {
@@ -131,7 +159,7 @@ func runMain() {
 	}
 
 	// Run X event loop
-	xevent.Main(store.X)
+	store.Main()
 }
 
 func InitLock() *os.File {
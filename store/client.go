@@ -26,11 +26,12 @@ import (
 )
 
 type Client struct {
-	Window   *XWindow // X window object
-	Original *Info    `json:"-"` // Original client window information
-	Cached   *Info    `json:"-"` // Cached client window information
-	Latest   *Info    // Latest client window information
-	Locked   bool     // Internal client move/resize lock
+	Window     *XWindow // X window object
+	Original   *Info    `json:"-"` // Original client window information
+	Cached     *Info    `json:"-"` // Cached client window information
+	Latest     *Info    // Latest client window information
+	Locked     bool     // Internal client move/resize lock
+	Decoration *bool    // Window decoration override of workspace
 }
 
 type Info struct {
@@ -54,6 +55,7 @@ type Dimensions struct {
 type Hints struct {
 	Normal icccm.NormalHints // Client window geometry hints
 	Motif  motif.Hints       // Client window decoration hints
+	Wm     icccm.Hints       // Client window manager hints
 }
 
 const (
@@ -234,6 +236,15 @@ func (c *Client) MoveWindow(x, y, w, h int) {
 	c.UnMaximize()
 	c.UnFullscreen()
 
+	// Move and/or resize window
+	c.DragWindow(x, y, w, h)
+
+	// Update stored dimensions
+	c.Update()
+}
+
+func (c *Client) DragWindow(x, y, w, h int) {
+
 	// Calculate dimension offsets
 	ext := c.Latest.Dimensions.Extents
 	dx, dy, dw, dh := 0, 0, 0, 0
@@ -245,15 +256,12 @@ func (c *Client) MoveWindow(x, y, w, h int) {
 		dw, dh = ext.Left+ext.Right, ext.Top+ext.Bottom
 	}
 
-	// Move and/or resize window
+	// Move and/or resize window without updating stored dimensions
 	if w > 0 && h > 0 {
 		ewmh.MoveresizeWindow(X, c.Window.Id, x+dx, y+dy, w-dw, h-dh)
 	} else {
 		ewmh.MoveWindow(X, c.Window.Id, x+dx, y+dy)
 	}
-
-	// Update stored dimensions
-	c.Update()
 }
 
 func (c *Client) OuterGeometry() (x, y, w, h int) {
@@ -522,6 +530,10 @@ func IsAbove(info *Info) bool {
 	return common.IsInList("_NET_WM_STATE_ABOVE", info.States)
 }
 
+func IsUrgent(info *Info) bool {
+	return common.IsInList("_NET_WM_STATE_DEMANDS_ATTENTION", info.States) || info.Dimensions.Hints.Wm.Flags&icccm.HintUrgency != 0
+}
+
 func GetInfo(w xproto.Window) *Info {
 	var err error
 
@@ -590,6 +602,12 @@ func GetInfo(w xproto.Window) *Info {
 		mhints = &motif.Hints{}
 	}
 
+	// Window manager hints (urgency hints of the window)
+	whints, err := icccm.WmHintsGet(X, w)
+	if err != nil {
+		whints = &icccm.Hints{}
+	}
+
 	// Window extents (server/client decorations of the window)
 	extNet, _ := xprop.PropValNums(xprop.GetProperty(X, w, "_NET_FRAME_EXTENTS"))
 	extGtk, _ := xprop.PropValNums(xprop.GetProperty(X, w, "_GTK_FRAME_EXTENTS"))
@@ -608,6 +626,7 @@ func GetInfo(w xproto.Window) *Info {
 		Hints: Hints{
 			Normal: *nhints,
 			Motif:  *mhints,
+			Wm:     *whints,
 		},
 		Extents: ewmh.FrameExtents{
 			Left:   int(ext[0]),
@@ -1,6 +1,8 @@
 package store
 
 import (
+	"time"
+
 	"github.com/leukipp/cortile/v2/common"
 
 	log "github.com/sirupsen/logrus"
@@ -9,8 +11,13 @@ import (
 type Corner struct {
 	Name     string          // Corner name used in config
 	Active   bool            // Mouse pointer is in this corner
+	Hot      bool            // Corner fired since pointer entered
 	Screen   uint            // Screen index the corner is located
 	Geometry common.Geometry // Geometry of the corner section
+	Entered  int64           // Time the pointer entered the corner
+	Fired    int64           // Time the corner fired last
+	Travel   int             // Pointer distance moved along screen edge
+	Position common.Point    // Last pointer position inside corner
 }
 
 func CreateCorner(name string, screen uint, x int, y int, w int, h int) *Corner {
@@ -61,6 +68,37 @@ func (c *Corner) IsActive(p *XPointer) bool {
 	return c.Active
 }
 
+func (c *Corner) IsHot() bool {
+	now := time.Now().UnixMilli()
+
+	// Check dwell time
+	if now-c.Entered < int64(common.Config.EdgeCornerDelay) {
+		return false
+	}
+
+	// Check cooldown time
+	if c.Fired > 0 && now-c.Fired < int64(common.Config.EdgeCornerCooldown) {
+		return false
+	}
+
+	// Check travel along screen edge
+	if c.Travel < common.Config.EdgeCornerTravel {
+		return false
+	}
+
+	return true
+}
+
+func (c *Corner) IsEdge(p *XPointer) bool {
+	if int(c.Screen) >= len(Workplace.Displays.Screens) {
+		return false
+	}
+	x, y, w, h := Workplace.Displays.Screens[c.Screen].Geometry.Pieces()
+
+	// Check if pointer touches screen edge
+	return p.Position.X == x || p.Position.X == x+w-1 || p.Position.Y == y || p.Position.Y == y+h-1
+}
+
 func HotCorner() *Corner {
 
 	// Update active states
@@ -70,17 +108,43 @@ func HotCorner() *Corner {
 		wasActive := hc.Active
 		isActive := hc.IsActive(Pointer)
 
-		// Corner is hot
+		// Corner was entered
 		if !wasActive && isActive {
-			log.Debug("Corner at position ", hc.Geometry, " is hot [", hc.Name, "]")
-			return hc
+			hc.Hot, hc.Entered, hc.Travel, hc.Position = false, time.Now().UnixMilli(), 0, Pointer.Position
+			log.Debug("Corner at position ", hc.Geometry, " is active [", hc.Name, "]")
 		}
 
-		// Corner was hot
+		// Corner was left
 		if wasActive && !isActive {
 			log.Debug("Corner at position ", hc.Geometry, " is cold [", hc.Name, "]")
 		}
+		if !isActive || hc.Hot {
+			continue
+		}
+
+		// Accumulate pointer movement at screen edge
+		if hc.IsEdge(Pointer) {
+			dx, dy := Pointer.Position.X-hc.Position.X, Pointer.Position.Y-hc.Position.Y
+			hc.Travel += max(dx, -dx) + max(dy, -dy)
+		}
+		hc.Position = Pointer.Position
+
+		// Corner is hot
+		if hc.IsHot() {
+			hc.Hot, hc.Fired = true, time.Now().UnixMilli()
+			log.Debug("Corner at position ", hc.Geometry, " is hot [", hc.Name, "]")
+			return hc
+		}
 	}
 
 	return nil
 }
+
+func ActiveCorner() *Corner {
+	for _, hc := range Workplace.Displays.Corners {
+		if hc.Active {
+			return hc
+		}
+	}
+	return nil
+}
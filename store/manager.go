@@ -129,6 +129,43 @@ func (mg *Manager) MakeMaster(c *Client) {
 	}
 }
 
+func (mg *Manager) InsertClient(c *Client, master bool) {
+	log.Info("Insert window [", c.Latest.Class, ", ", mg.Name, "]")
+
+	// Remove window from master and slave area
+	if i := mg.Index(mg.Masters, c); i >= 0 {
+		mg.Masters.Stacked = removeClient(mg.Masters.Stacked, i)
+	}
+	if i := mg.Index(mg.Slaves, c); i >= 0 {
+		mg.Slaves.Stacked = removeClient(mg.Slaves.Stacked, i)
+	}
+
+	// Insert window as first master
+	if master {
+		mg.Masters.Stacked = addClient(mg.Masters.Stacked, c)
+
+		// Move overflowing master to slave area
+		if n := len(mg.Masters.Stacked) - 1; n >= mg.Masters.Maximum {
+			mg.Slaves.Stacked = addClient(mg.Slaves.Stacked, mg.Masters.Stacked[n])
+			mg.Masters.Stacked = mg.Masters.Stacked[:n]
+		}
+		return
+	}
+
+	// Fill up master area with other windows
+	for len(mg.Masters.Stacked) < mg.Masters.Maximum && len(mg.Slaves.Stacked) > 0 {
+		mg.Masters.Stacked = append(mg.Masters.Stacked, mg.Slaves.Stacked[0])
+		mg.Slaves.Stacked = mg.Slaves.Stacked[1:]
+	}
+
+	// Insert window as first slave
+	if len(mg.Masters.Stacked) < mg.Masters.Maximum {
+		mg.Masters.Stacked = append(mg.Masters.Stacked, c)
+	} else {
+		mg.Slaves.Stacked = addClient(mg.Slaves.Stacked, c)
+	}
+}
+
 func (mg *Manager) SwapClient(c1 *Client, c2 *Client) {
 	log.Info("Swap clients [", c1.Latest.Class, "-", c2.Latest.Class, ", ", mg.Name, "]")
 
@@ -2,6 +2,7 @@ package store
 
 import (
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 	"time"
@@ -29,6 +30,10 @@ var (
 	Windows       *XWindows       // X windows
 )
 
+var (
+	Calls = make(chan func(), 100) // Functions executed on the X event loop
+)
+
 type XWindowManager struct {
 	Name string // Window manager name
 }
@@ -206,6 +211,43 @@ func Compatible(feature string) bool {
 	return true
 }
 
+func Main() {
+
+	// Run X event handlers and queued functions in turn
+	pingBefore, pingAfter, pingQuit := xevent.MainPing(X)
+	for {
+		select {
+		case <-pingBefore:
+			<-pingAfter
+		case fun := <-Calls:
+			fun()
+		case <-pingQuit:
+			return
+		}
+	}
+}
+
+func Enqueue(fun func()) {
+
+	// Queue function for the X event loop
+	select {
+	case Calls <- fun:
+	default:
+		go func() { Calls <- fun }()
+	}
+}
+
+func Execute(fun func()) {
+	done := make(chan bool)
+
+	// Queue function and wait until the X event loop ran it
+	Enqueue(func() {
+		defer close(done)
+		fun()
+	})
+	<-done
+}
+
 func NumberOfDesktopsGet(X *xgbutil.XUtil) uint {
 	deskCount, err := ewmh.NumberOfDesktopsGet(X)
 
@@ -430,6 +472,15 @@ func PointerGet(X *xgbutil.XUtil) *XPointer {
 	}
 }
 
+func PointerSet(X *xgbutil.XUtil, p common.Point) {
+
+	// Move pointer to absolute position
+	err := xproto.WarpPointerChecked(X.Conn(), xproto.WindowNone, X.RootWin(), 0, 0, 0, 0, int16(p.X), int16(p.Y)).Check()
+	if err != nil {
+		log.Warn("Error setting pointer position: ", err)
+	}
+}
+
 func ScreenGet(p common.Point) uint {
 
 	// Check if point is inside screen rectangle
@@ -442,6 +493,56 @@ func ScreenGet(p common.Point) uint {
 	return 0
 }
 
+func ScreenNeighbor(i uint, d *Directions) (uint, bool) {
+	if int(i) >= len(Workplace.Displays.Screens) {
+		return 0, false
+	}
+	from := Workplace.Displays.Screens[i].Geometry
+
+	// Find nearest screen in direction
+	neighbor, best := uint(0), -1
+	for j, screen := range Workplace.Displays.Screens {
+		if uint(j) == i {
+			continue
+		}
+		distance, ok := DirectionDistance(from, screen.Geometry, d)
+		if ok && (best < 0 || distance < best) {
+			neighbor, best = uint(j), distance
+		}
+	}
+
+	return neighbor, best >= 0
+}
+
+func DirectionDistance(from common.Geometry, to common.Geometry, d *Directions) (int, bool) {
+	fc, tc := from.Center(), to.Center()
+
+	// Distance along and offset across the direction
+	along, gap, offset := 0, 0, 0
+	switch {
+	case d.Left:
+		along, gap, offset = fc.X-tc.X, intervalGap(from.Y, from.Height, to.Y, to.Height), tc.Y-fc.Y
+	case d.Right:
+		along, gap, offset = tc.X-fc.X, intervalGap(from.Y, from.Height, to.Y, to.Height), tc.Y-fc.Y
+	case d.Top:
+		along, gap, offset = fc.Y-tc.Y, intervalGap(from.X, from.Width, to.X, to.Width), tc.X-fc.X
+	case d.Bottom:
+		along, gap, offset = tc.Y-fc.Y, intervalGap(from.X, from.Width, to.X, to.Width), tc.X-fc.X
+	}
+
+	// Ignore geometries behind the direction
+	if along <= 0 {
+		return 0, false
+	}
+
+	// Prefer overlapping edges, then nearest centers
+	return along + 2*gap + int(math.Abs(float64(offset)))/4, true
+}
+
+func intervalGap(a int, la int, b int, lb int) int {
+	return common.MaxInt(0, common.MaxInt(a, b)-common.MinInt(a+la, b+lb))
+}
+
 func ScreenGeometry(i uint) *common.Geometry {
 	if int(i) >= len(Workplace.Displays.Screens) {
 		return &common.Geometry{}
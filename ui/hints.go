@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"image"
+	"strings"
+
+	"golang.org/x/image/font/gofont/goregular"
+
+	"github.com/BurntSushi/freetype-go/freetype/truetype"
+
+	"github.com/jezek/xgb/xproto"
+
+	"github.com/jezek/xgbutil/xgraphics"
+	"github.com/jezek/xgbutil/xwindow"
+
+	"github.com/leukipp/cortile/v2/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	hintSize   int = 32 // Size of hint font
+	hintMargin int = 10 // Margin of hint font
+)
+
+var (
+	hints []*xwindow.Window // Hint label windows
+)
+
+func ShowHints(labels map[string]*store.Client) *xwindow.Window {
+	CloseHints()
+
+	font, err := truetype.Parse(goregular.TTF)
+	if err != nil {
+		log.Error("Parsing font failed: ", err)
+		return nil
+	}
+
+	// Draw hint label for each client
+	for label, c := range labels {
+		txt := strings.ToUpper(label)
+
+		// Create an empty canvas image
+		tw, th := xgraphics.Extents(font, float64(hintSize), txt)
+		w, h := tw+2*hintMargin, th+2*hintMargin
+		bg := bgra("gui_client_master")
+		cv := xgraphics.New(store.X, image.Rect(0, 0, w, h))
+		cv.For(func(x int, y int) xgraphics.BGRA { return bg })
+
+		// Draw label text
+		cv.Text(hintMargin, hintMargin, bgra("gui_text"), float64(hintSize), font, txt)
+
+		// Show label in the center of client
+		cx, cy, cw, ch := c.OuterGeometry()
+		win := showHint(cv, cx+cw/2-w/2, cy+ch/2-h/2)
+		if win == nil {
+			continue
+		}
+		hints = append(hints, win)
+	}
+
+	if len(hints) == 0 {
+		return nil
+	}
+
+	return hints[0]
+}
+
+func CloseHints() {
+
+	// Destroy hint label windows
+	for _, win := range hints {
+		win.Destroy()
+	}
+	hints = nil
+}
+
+func showHint(img *xgraphics.Image, x int, y int) *xwindow.Window {
+	win, err := xwindow.Generate(img.X)
+	if err != nil {
+		log.Error("Graphics generation failed: ", err)
+		return nil
+	}
+
+	// Create the unmanaged graphics window
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	win.Create(img.X.RootWin(), x, y, w, h, xproto.CwOverrideRedirect, 1)
+
+	// Paint the image and map the window
+	img.XSurfaceSet(win.Id)
+	img.XDraw()
+	img.XPaint(win.Id)
+	win.Map()
+
+	return win
+}
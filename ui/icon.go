@@ -2,6 +2,7 @@ package ui
 
 import (
 	"bytes"
+	"fmt"
 	"image"
 
 	"image/color"
@@ -23,8 +24,40 @@ var (
 	iconSize     int = 256 // Size of systray icon
 	iconMargin   int = 36  // Margin of systray icon
 	layoutMargin int = 12  // Margin of layout rectangles
+	urgentCount  int = 0   // Number of urgent windows
 )
 
+var (
+	mode string // Active keybinding mode
+)
+
+func UpdateMode(ws *desktop.Workspace, name string) {
+	mode = name
+	if len(common.Config.TilingIcon) == 0 {
+		return
+	}
+
+	// Update systray tooltip
+	tooltip := fmt.Sprintf("%s - tiling manager", common.Build.Name)
+	if len(mode) > 0 {
+		tooltip = fmt.Sprintf("%s - %s mode", common.Build.Name, mode)
+	}
+	systray.SetTooltip(tooltip)
+
+	// Update systray icon
+	UpdateIcon(ws)
+}
+
+func UpdateUrgent(ws *desktop.Workspace, count int) {
+	if count == urgentCount {
+		return
+	}
+	urgentCount = count
+
+	// Update systray icon
+	UpdateIcon(ws)
+}
+
 func UpdateIcon(ws *desktop.Workspace) {
 	location := store.Location{Desktop: store.Workplace.CurrentDesktop, Screen: store.Workplace.CurrentScreen}
 	if ws == nil || ws.Location != location || len(common.Config.TilingIcon) == 0 {
@@ -92,6 +125,30 @@ func UpdateIcon(ws *desktop.Workspace) {
 		draw.Draw(icon, image.Rect(x1-dx, y1-dy, x1+dx, y1+dy), &col, image.Point{}, draw.Src)
 	}
 
+	// Draw urgent rectangle
+	if urgentCount > 0 {
+		col := image.Uniform{color.RGBA{
+			R: uint8(230),
+			G: uint8(20),
+			B: uint8(20),
+			A: uint8(255),
+		}}
+		dx, dy := iconSize/10, iconSize/10
+		draw.Draw(icon, image.Rect(x1-dx, y0-dy, x1+dx, y0+dy), &col, image.Point{}, draw.Src)
+	}
+
+	// Draw mode rectangle
+	if len(mode) > 0 {
+		col := image.Uniform{color.RGBA{
+			R: uint8(30),
+			G: uint8(140),
+			B: uint8(250),
+			A: uint8(255),
+		}}
+		dx, dy := iconSize/10, iconSize/10
+		draw.Draw(icon, image.Rect(x0-dx, y0-dy, x0+dx, y0+dy), &col, image.Point{}, draw.Src)
+	}
+
 	// Encode image bytes
 	data := new(bytes.Buffer)
 	png.Encode(data, icon)
@@ -70,6 +70,38 @@ func ShowLayout(ws *desktop.Workspace) {
 	})
 }
 
+func ShowKeys(ws *desktop.Workspace, lines []string) *xwindow.Window {
+	if ws == nil || len(lines) == 0 {
+		return nil
+	}
+
+	font, err := truetype.Parse(goregular.TTF)
+	if err != nil {
+		log.Error("Parsing font failed: ", err)
+		return nil
+	}
+
+	// Calculate text dimensions
+	w, h := 0, len(lines)*(fontSize+2*fontMargin)
+	for _, line := range lines {
+		lw, _ := xgraphics.Extents(font, float64(fontSize), line)
+		w = common.MaxInt(w, lw)
+	}
+
+	// Create an empty canvas image
+	bg := bgra("gui_background")
+	cv := xgraphics.New(store.X, image.Rect(0, 0, w+2*fontMargin+2*rectMargin, h+2*rectMargin))
+	cv.For(func(x int, y int) xgraphics.BGRA { return bg })
+
+	// Draw key lines
+	for i, line := range lines {
+		cv.Text(fontMargin+rectMargin, rectMargin+fontMargin+i*(fontSize+2*fontMargin), bgra("gui_text"), float64(fontSize), font, line)
+	}
+
+	// Show the canvas graphics
+	return showGraphics(cv, ws, 0)
+}
+
 // ShowWindowFloat muestra un overlay visual representativo al hacer toggle
 // de ventana flotante. Para 'floating' dibuja un rectángulo desplazado sobre
 // un fondo (ventana flotando encima del tiling). Para 'tiling' muestra el
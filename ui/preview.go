@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"github.com/jezek/xgb/xproto"
+
+	"github.com/jezek/xgbutil/xprop"
+	"github.com/jezek/xgbutil/xwindow"
+
+	"github.com/leukipp/cortile/v2/common"
+	"github.com/leukipp/cortile/v2/store"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	preview     *xwindow.Window // Drop preview window
+	previewGeom common.Geometry // Drop preview dimensions
+)
+
+func ShowPreview(geom common.Geometry) {
+	if preview != nil && previewGeom == geom {
+		return
+	}
+	ClosePreview()
+
+	win, err := xwindow.Generate(store.X)
+	if err != nil {
+		log.Error("Graphics generation failed: ", err)
+		return
+	}
+
+	// Create the unmanaged preview window
+	color := bgra("gui_preview")
+	pixel := uint32(color.R)<<16 | uint32(color.G)<<8 | uint32(color.B)
+	x, y, w, h := geom.Pieces()
+	win.Create(store.X.RootWin(), x, y, w, h, xproto.CwBackPixel|xproto.CwOverrideRedirect, pixel, 1)
+
+	// Set opacity for compositing window managers
+	opacity := uint(color.A) * 0x01010101
+	xprop.ChangeProp32(store.X, win.Id, "_NET_WM_WINDOW_OPACITY", "CARDINAL", opacity)
+
+	// Map the window
+	win.Map()
+
+	preview, previewGeom = win, geom
+}
+
+func ClosePreview() {
+	if preview == nil {
+		return
+	}
+
+	// Destroy preview window
+	preview.Destroy()
+	preview = nil
+}